@@ -0,0 +1,109 @@
+// Package branchfilter implements Buildkite's branch filter patterns, as used to choose which
+// branches a pipeline builds.
+//
+// A filter is a space separated list of patterns. A * in a pattern matches any sequence of
+// characters, including /. Patterns starting with ! exclude the branches they match. A branch is
+// accepted if it matches at least one pattern and no excluding pattern. A filter made up of only
+// excluding patterns, or no patterns at all, accepts every other branch.
+package branchfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// invalidSequences can't appear in a git branch name, so a pattern containing one never matches.
+var invalidSequences = []string{"..", "@{", "//", "\\", "?", "[", "~", "^", ":"}
+
+// Error describes a malformed pattern.
+type Error struct {
+	// Column is the position of the pattern in the filter, starting from 1.
+	Column  int
+	Pattern string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %q %s", e.Column, e.Pattern, e.Message)
+}
+
+// Filter is a parsed branch filter.
+type Filter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// Parse parses a branch filter, returning an *Error for the first malformed pattern.
+func Parse(filter string) (*Filter, error) {
+	f := &Filter{}
+	column := 0
+	for _, field := range strings.SplitAfter(filter, " ") {
+		pattern := strings.TrimRight(field, " ")
+		start := column + 1
+		column += len(field)
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		if strings.ContainsAny(pattern, "\t\n\r") {
+			return nil, &Error{Column: start, Pattern: pattern, Message: "must be separated by spaces, not tabs or newlines"}
+		}
+		negated := strings.HasPrefix(pattern, "!")
+		glob := strings.TrimPrefix(pattern, "!")
+		if err := checkGlob(glob); err != "" {
+			return nil, &Error{Column: start, Pattern: pattern, Message: err}
+		}
+		re := compile(glob)
+		if negated {
+			f.exclude = append(f.exclude, re)
+		} else {
+			f.include = append(f.include, re)
+		}
+	}
+	return f, nil
+}
+
+func checkGlob(glob string) string {
+	switch {
+	case glob == "":
+		return "has nothing to negate"
+	case strings.HasPrefix(glob, "!"):
+		return "can only be negated once"
+	case strings.HasPrefix(glob, "/") || strings.HasSuffix(glob, "/"):
+		return "can't start or end with /"
+	case strings.HasSuffix(glob, ".lock"):
+		return "can't end with .lock"
+	}
+	for _, s := range invalidSequences {
+		if strings.Contains(glob, s) {
+			return fmt.Sprintf("can't contain %q, as no branch name can", s)
+		}
+	}
+	return ""
+}
+
+func compile(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// Match reports whether the filter accepts the branch.
+func (f *Filter) Match(branch string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(branch) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(branch) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package branchfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		filter   string
+		branch   string
+		expected bool
+	}{
+		{filter: "", branch: "master", expected: true},
+		{filter: "master", branch: "master", expected: true},
+		{filter: "master", branch: "main", expected: false},
+		{filter: "master", branch: "master2", expected: false},
+		{filter: "master feature/*", branch: "feature/login", expected: true},
+		{filter: "master feature/*", branch: "feature/auth/login", expected: true},
+		{filter: "master feature/*", branch: "bugfix/login", expected: false},
+		{filter: "*-release", branch: "v1-release", expected: true},
+		{filter: "release/*/hotfix", branch: "release/1.2/hotfix", expected: true},
+		{filter: "!master", branch: "master", expected: false},
+		{filter: "!master", branch: "feature/login", expected: true},
+		{filter: "feature/* !feature/wip-*", branch: "feature/wip-login", expected: false},
+		{filter: "feature/* !feature/wip-*", branch: "feature/login", expected: true},
+		{filter: "feature/* !feature/wip-*", branch: "master", expected: false},
+		{filter: "  master   main ", branch: "main", expected: true},
+		{filter: "v1.0", branch: "v1x0", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter+"/"+tc.branch, func(t *testing.T) {
+			f, err := Parse(tc.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, f.Match(tc.branch))
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testCases := []struct {
+		filter   string
+		expected string
+	}{
+		{filter: "master !", expected: `column 8: "!" has nothing to negate`},
+		{filter: "!!master", expected: `column 1: "!!master" can only be negated once`},
+		{filter: "feature/[ab]", expected: `column 1: "feature/[ab]" can't contain "[", as no branch name can`},
+		{filter: "master release..1", expected: `column 8: "release..1" can't contain "..", as no branch name can`},
+		{filter: "feature/", expected: `column 1: "feature/" can't start or end with /`},
+		{filter: "master\tmain", expected: `column 1: "master\tmain" must be separated by spaces, not tabs or newlines`},
+		{filter: "main.lock", expected: `column 1: "main.lock" can't end with .lock`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			_, err := Parse(tc.filter)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
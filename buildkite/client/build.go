@@ -0,0 +1,74 @@
+package client
+
+import (
+	"strconv"
+
+	buildkiteRest "github.com/buildkite/go-buildkite/v2/buildkite"
+)
+
+// Build is a build of a pipeline, as returned by the REST API.
+type Build = buildkiteRest.Build
+
+// CreateBuild describes a build to create.
+type CreateBuild = buildkiteRest.CreateBuild
+
+// FinishedBuildStates are the build states that won't change any more. Blocked builds are waiting for
+// someone to unblock them.
+var FinishedBuildStates = []string{"passed", "failed", "blocked", "canceled", "skipped", "not_run"}
+
+// CreateBuild creates a build of the pipeline with the given slug.
+func (c *Client) CreateBuild(pipelineSlug string, build *CreateBuild) (*Build, error) {
+	b, _, err := c.restClient.Builds.Create(c.orgSlug, pipelineSlug, build)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ReadBuild reads the build with the given number of the pipeline with the given slug.
+func (c *Client) ReadBuild(pipelineSlug string, number int) (*Build, error) {
+	b, _, err := c.restClient.Builds.Get(c.orgSlug, pipelineSlug, strconv.Itoa(number), nil)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// BuildsListOptions filters the builds returned by ListBuilds.
+type BuildsListOptions = buildkiteRest.BuildsListOptions
+
+// ListBuilds lists the builds of the pipeline with the given slug, newest first. Only a single page
+// is returned, sized by opt.PerPage.
+func (c *Client) ListBuilds(pipelineSlug string, opt *BuildsListOptions) ([]Build, error) {
+	builds, _, err := c.restClient.Builds.ListByPipeline(c.orgSlug, pipelineSlug, opt)
+	if err != nil {
+		return nil, err
+	}
+	return builds, nil
+}
+
+// ListAllBuilds lists the builds of the pipeline with the given slug, newest first, following every
+// page from opt.Page.
+func (c *Client) ListAllBuilds(pipelineSlug string, opt *BuildsListOptions) ([]Build, error) {
+	var result []Build
+	for {
+		builds, resp, err := c.restClient.Builds.ListByPipeline(c.orgSlug, pipelineSlug, opt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, builds...)
+		if resp.NextPage == 0 {
+			return result, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// Timestamp is a time returned by the REST API.
+type Timestamp = buildkiteRest.Timestamp
+
+// CancelBuild cancels the build with the given number of the pipeline with the given slug.
+func (c *Client) CancelBuild(pipelineSlug string, number int) error {
+	_, err := c.restClient.Builds.Cancel(c.orgSlug, pipelineSlug, strconv.Itoa(number))
+	return err
+}
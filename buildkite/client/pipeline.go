@@ -27,7 +27,300 @@ func (c *Client) GetPipelineID(slug string) (string, error) {
 	return string(query.Pipeline.ID), nil
 }
 
-func (c *Client) CreatePipeline(pipeline *Pipeline) error {
+// GetPipelineSlugByUUID returns the slug of the pipeline with the given UUID.
+func (c *Client) GetPipelineSlugByUUID(uuid string) (string, error) {
+	var query struct {
+		Pipeline struct {
+			Slug graphql.String
+		} `graphql:"pipeline(uuid: $uuid)"`
+	}
+	vars := map[string]interface{}{
+		"uuid": uuid,
+	}
+	if err := c.gqlClient.Query(context.TODO(), &query, vars); err != nil {
+		return "", err
+	}
+	if query.Pipeline.Slug == "" {
+		return "", fmt.Errorf("no pipeline with UUID %s", uuid)
+	}
+	return string(query.Pipeline.Slug), nil
+}
+
+// GetPipelineSlugByID returns the slug of the pipeline with the given gql ID.
+func (c *Client) GetPipelineSlugByID(id string) (string, error) {
+	var query struct {
+		Node struct {
+			Pipeline struct {
+				Slug graphql.String
+			} `graphql:"... on Pipeline"`
+		} `graphql:"node(id: $id)"`
+	}
+	vars := map[string]interface{}{
+		"id": id,
+	}
+	if err := c.gqlClient.Query(context.TODO(), &query, vars); err != nil {
+		return "", err
+	}
+	if query.Node.Pipeline.Slug == "" {
+		return "", fmt.Errorf("no pipeline with ID %s", id)
+	}
+	return string(query.Node.Pipeline.Slug), nil
+}
+
+// PipelineSummary is a pipeline as returned when listing the pipelines of the organization.
+type PipelineSummary struct {
+	ID         graphql.String
+	Slug       graphql.String
+	Name       graphql.String
+	Repository struct {
+		URL graphql.String
+	}
+	Tags    []PipelineTag
+	Cluster struct {
+		ID graphql.String
+	}
+	// Teams holds every team with access to the pipeline once it is returned by ListPipelines.
+	Teams PipelineTeams `graphql:"teams(first: 50)"`
+}
+
+// PipelineTeams is a page of the teams with access to a pipeline.
+type PipelineTeams struct {
+	PageInfo struct {
+		HasNextPage graphql.Boolean
+		EndCursor   graphql.String
+	}
+	Edges []PipelineTeamEdge
+}
+
+// PipelineTeamEdge is a team with access to a pipeline.
+type PipelineTeamEdge struct {
+	Node struct {
+		Team struct {
+			ID graphql.String
+		}
+	}
+}
+
+// ListPipelines returns every pipeline in the organization whose name matches search, or every
+// pipeline if search is empty.
+func (c *Client) ListPipelines(search string) ([]PipelineSummary, error) {
+	var query struct {
+		Organization struct {
+			Pipelines struct {
+				PageInfo struct {
+					HasNextPage graphql.Boolean
+					EndCursor   graphql.String
+				}
+				Edges []struct {
+					Node PipelineSummary
+				}
+			} `graphql:"pipelines(first: 100, after: $cursor, search: $search)"`
+		} `graphql:"organization(slug: $slug)"`
+	}
+	vars := map[string]interface{}{
+		"slug":   c.orgSlug,
+		"cursor": (*graphql.String)(nil),
+		"search": (*graphql.String)(nil),
+	}
+	if search != "" {
+		vars["search"] = graphql.NewString(graphql.String(search))
+	}
+
+	result := []PipelineSummary{}
+	for {
+		if err := c.gqlClient.Query(context.TODO(), &query, vars); err != nil {
+			return nil, err
+		}
+		for _, edge := range query.Organization.Pipelines.Edges {
+			p := edge.Node
+			if err := c.readRemainingPipelineTeams(&p); err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		if !query.Organization.Pipelines.PageInfo.HasNextPage {
+			return result, nil
+		}
+		vars["cursor"] = graphql.NewString(query.Organization.Pipelines.PageInfo.EndCursor)
+	}
+}
+
+// readRemainingPipelineTeams appends the teams of the pipeline that weren't on the first page.
+func (c *Client) readRemainingPipelineTeams(p *PipelineSummary) error {
+	var query struct {
+		Node struct {
+			Pipeline struct {
+				Teams PipelineTeams `graphql:"teams(first: 100, after: $cursor)"`
+			} `graphql:"... on Pipeline"`
+		} `graphql:"node(id: $id)"`
+	}
+	for p.Teams.PageInfo.HasNextPage {
+		vars := map[string]interface{}{
+			"id":     string(p.ID),
+			"cursor": p.Teams.PageInfo.EndCursor,
+		}
+		if err := c.gqlClient.Query(context.TODO(), &query, vars); err != nil {
+			return err
+		}
+		p.Teams.Edges = append(p.Teams.Edges, query.Node.Pipeline.Teams.Edges...)
+		p.Teams.PageInfo = query.Node.Pipeline.Teams.PageInfo
+	}
+	return nil
+}
+
+// PipelineDetails are the attributes of a pipeline that are only exposed by the GraphQL API.
+type PipelineDetails struct {
+	ID       graphql.String
+	Archived graphql.Boolean
+	// Visibility is either PUBLIC or PRIVATE.
+	Visibility graphql.String
+	Emoji      graphql.String
+	// Color is a hex color such as #FF0000.
+	Color graphql.String
+	Tags  []PipelineTag
+	// Cluster is empty for pipelines in the unclustered pool.
+	Cluster struct {
+		ID graphql.String
+	}
+	// PipelineTemplate is empty for pipelines that use their own step configuration.
+	PipelineTemplate struct {
+		ID graphql.String
+	}
+	// The timeouts for command steps, in minutes. Zero means no timeout.
+	DefaultTimeoutInMinutes graphql.Int
+	MaximumTimeoutInMinutes graphql.Int
+}
+
+// PipelineTag is a label used to group and filter pipelines.
+type PipelineTag struct {
+	Label graphql.String
+}
+
+// ReadPipelineDetails returns the GraphQL only attributes of a pipeline specified by its slug.
+func (c *Client) ReadPipelineDetails(slug string) (*PipelineDetails, error) {
+	var query struct {
+		Pipeline PipelineDetails `graphql:"pipeline(slug: $slug)"`
+	}
+	vars := map[string]interface{}{
+		"slug": fmt.Sprintf("%s/%s", c.orgSlug, slug),
+	}
+	if err := c.gqlClient.Query(context.TODO(), &query, vars); err != nil {
+		return nil, err
+	}
+	return &query.Pipeline, nil
+}
+
+// UpdatePipelineDetails syncs the GraphQL only attributes of the given pipeline with Buildkite.
+func (c *Client) UpdatePipelineDetails(details *PipelineDetails) error {
+	var mutation struct {
+		PipelineUpdate struct {
+			Pipeline PipelineDetails
+		} `graphql:"pipelineUpdate(input: $input)"`
+	}
+	type PipelineTagInput struct {
+		Label string `json:"label"`
+	}
+	type PipelineUpdateInput struct {
+		ID         string             `json:"id"`
+		Visibility string             `json:"visibility,omitempty"`
+		Emoji      string             `json:"emoji"`
+		Color      string             `json:"color"`
+		Tags       []PipelineTagInput `json:"tags"`
+		ClusterID  *string            `json:"clusterId"`
+
+		PipelineTemplateID      *string `json:"pipelineTemplateId"`
+		DefaultTimeoutInMinutes *int    `json:"defaultTimeoutInMinutes"`
+		MaximumTimeoutInMinutes *int    `json:"maximumTimeoutInMinutes"`
+	}
+	tags := []PipelineTagInput{}
+	for _, tag := range details.Tags {
+		tags = append(tags, PipelineTagInput{Label: string(tag.Label)})
+	}
+	var clusterID *string
+	if details.Cluster.ID != "" {
+		id := string(details.Cluster.ID)
+		clusterID = &id
+	}
+	var pipelineTemplateID *string
+	if details.PipelineTemplate.ID != "" {
+		id := string(details.PipelineTemplate.ID)
+		pipelineTemplateID = &id
+	}
+	var defaultTimeout, maximumTimeout *int
+	if details.DefaultTimeoutInMinutes != 0 {
+		timeout := int(details.DefaultTimeoutInMinutes)
+		defaultTimeout = &timeout
+	}
+	if details.MaximumTimeoutInMinutes != 0 {
+		timeout := int(details.MaximumTimeoutInMinutes)
+		maximumTimeout = &timeout
+	}
+	vars := map[string]interface{}{
+		"input": PipelineUpdateInput{
+			ID:         string(details.ID),
+			Visibility: string(details.Visibility),
+			Emoji:      string(details.Emoji),
+			Color:      string(details.Color),
+			Tags:       tags,
+			ClusterID:  clusterID,
+
+			PipelineTemplateID:      pipelineTemplateID,
+			DefaultTimeoutInMinutes: defaultTimeout,
+			MaximumTimeoutInMinutes: maximumTimeout,
+		},
+	}
+
+	if err := c.gqlClient.Mutate(context.TODO(), &mutation, vars); err != nil {
+		return err
+	}
+	*details = mutation.PipelineUpdate.Pipeline
+	return nil
+}
+
+// ArchivePipeline archives the pipeline with the given gql ID. Archived pipelines keep their builds
+// but can no longer run new ones.
+func (c *Client) ArchivePipeline(id string) error {
+	var mutation struct {
+		PipelineArchive struct {
+			Pipeline struct {
+				ID graphql.String
+			}
+		} `graphql:"pipelineArchive(input: $input)"`
+	}
+	type PipelineArchiveInput struct {
+		ID string `json:"id"`
+	}
+	vars := map[string]interface{}{
+		"input": PipelineArchiveInput{
+			ID: id,
+		},
+	}
+	return c.gqlClient.Mutate(context.TODO(), &mutation, vars)
+}
+
+// UnarchivePipeline restores the archived pipeline with the given gql ID.
+func (c *Client) UnarchivePipeline(id string) error {
+	var mutation struct {
+		PipelineUnarchive struct {
+			Pipeline struct {
+				ID graphql.String
+			}
+		} `graphql:"pipelineUnarchive(input: $input)"`
+	}
+	type PipelineUnarchiveInput struct {
+		ID string `json:"id"`
+	}
+	vars := map[string]interface{}{
+		"input": PipelineUnarchiveInput{
+			ID: id,
+		},
+	}
+	return c.gqlClient.Mutate(context.TODO(), &mutation, vars)
+}
+
+// CreatePipeline creates the pipeline, giving the teams with the given UUIDs access to it from the
+// start.
+func (c *Client) CreatePipeline(pipeline *Pipeline, teamUUIDs []string) error {
 	safeString := func(s *string) string {
 		if s == nil {
 			return ""
@@ -49,9 +342,8 @@ func (c *Client) CreatePipeline(pipeline *Pipeline) error {
 		Steps: nil,
 		// Env is also part of the yaml steps.
 		Env: nil,
-		// Teams are associated through TeamPipelines rather than specifying them solely on
-		// Pipeline create.
-		TeamUuids: nil,
+		// Further teams can be associated through TeamPipelines.
+		TeamUuids: teamUUIDs,
 
 		Name:                            safeString(pipeline.Name),
 		Repository:                      safeString(pipeline.Repository),
@@ -85,11 +377,26 @@ func (c *Client) ReadPipeline(slug string) (*Pipeline, error) {
 	return p, nil
 }
 
+// UpdatePipeline syncs the pipeline with Buildkite. The pipeline is updated from the response,
+// including its slug, which changes when the pipeline is renamed.
 func (c *Client) UpdatePipeline(pipeline *Pipeline) error {
 	_, err := c.restClient.Pipelines.Update(c.orgSlug, pipeline)
 	return err
 }
 
+// CreatePipelineWebhook asks Buildkite to create the webhook that triggers builds on pushes to the
+// pipeline's repository. This only works for GitHub repositories connected through the Buildkite
+// GitHub App. go-buildkite has no method for it, so the request is made directly.
+func (c *Client) CreatePipelineWebhook(slug string) error {
+	u := fmt.Sprintf("v2/organizations/%s/pipelines/%s/webhook", c.orgSlug, slug)
+	req, err := c.restClient.NewRequest("POST", u, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.restClient.Do(req, nil)
+	return err
+}
+
 func (c *Client) DeletePipeline(pipeline *Pipeline) error {
 	_, err := c.restClient.Pipelines.Delete(c.orgSlug, *pipeline.Slug)
 	return err
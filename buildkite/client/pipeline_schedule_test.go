@@ -25,7 +25,7 @@ steps:
 		DefaultBranch: strPtr("master"),
 	}
 
-	if err := cli.CreatePipeline(p); err != nil {
+	if err := cli.CreatePipeline(p, nil); err != nil {
 		return p, err
 	}
 	return cli.ReadPipeline(name)
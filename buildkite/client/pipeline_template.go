@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+
+	"github.com/shurcooL/graphql"
+)
+
+// PipelineTemplate represents a pipeline template, which restricts the step configuration of the
+// pipelines using it.
+type PipelineTemplate struct {
+	ID            graphql.String
+	Name          graphql.String
+	Description   graphql.String
+	Configuration graphql.String
+	// Available templates can be chosen by anyone creating a pipeline, not just admins.
+	Available graphql.Boolean
+}
+
+// CreatePipelineTemplate creates the given pipeline template and if successful, adds an ID to it.
+func (c *Client) CreatePipelineTemplate(template *PipelineTemplate) error {
+	var mutation struct {
+		PipelineTemplateCreate struct {
+			PipelineTemplate PipelineTemplate
+		} `graphql:"pipelineTemplateCreate(input: $input)"`
+	}
+	type PipelineTemplateCreateInput struct {
+		OrganizationID string `json:"organizationId"`
+		Name           string `json:"name"`
+		Description    string `json:"description"`
+		Configuration  string `json:"configuration"`
+		Available      bool   `json:"available"`
+	}
+	vars := map[string]interface{}{
+		"input": PipelineTemplateCreateInput{
+			OrganizationID: c.orgID,
+			Name:           string(template.Name),
+			Description:    string(template.Description),
+			Configuration:  string(template.Configuration),
+			Available:      bool(template.Available),
+		},
+	}
+
+	if err := c.gqlClient.Mutate(context.TODO(), &mutation, vars); err != nil {
+		return err
+	}
+	template.ID = mutation.PipelineTemplateCreate.PipelineTemplate.ID
+	return nil
+}
+
+// ReadPipelineTemplate returns the pipeline template with the given gql ID.
+func (c *Client) ReadPipelineTemplate(id string) (*PipelineTemplate, error) {
+	var query struct {
+		Node struct {
+			PipelineTemplate PipelineTemplate `graphql:"... on PipelineTemplate"`
+		} `graphql:"node(id: $id)"`
+	}
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	if err := c.gqlClient.Query(context.TODO(), &query, vars); err != nil {
+		return nil, err
+	}
+	return &query.Node.PipelineTemplate, nil
+}
+
+// UpdatePipelineTemplate syncs the local pipeline template struct with Buildkite.
+func (c *Client) UpdatePipelineTemplate(template *PipelineTemplate) error {
+	var mutation struct {
+		PipelineTemplateUpdate struct {
+			PipelineTemplate PipelineTemplate
+		} `graphql:"pipelineTemplateUpdate(input: $input)"`
+	}
+	type PipelineTemplateUpdateInput struct {
+		OrganizationID string `json:"organizationId"`
+		ID             string `json:"id"`
+		Name           string `json:"name"`
+		Description    string `json:"description"`
+		Configuration  string `json:"configuration"`
+		Available      bool   `json:"available"`
+	}
+	vars := map[string]interface{}{
+		"input": PipelineTemplateUpdateInput{
+			OrganizationID: c.orgID,
+			ID:             string(template.ID),
+			Name:           string(template.Name),
+			Description:    string(template.Description),
+			Configuration:  string(template.Configuration),
+			Available:      bool(template.Available),
+		},
+	}
+
+	if err := c.gqlClient.Mutate(context.TODO(), &mutation, vars); err != nil {
+		return err
+	}
+	*template = mutation.PipelineTemplateUpdate.PipelineTemplate
+	return nil
+}
+
+// DeletePipelineTemplate deletes the given pipeline template based on the ID field.
+func (c *Client) DeletePipelineTemplate(template *PipelineTemplate) error {
+	var mutation struct {
+		PipelineTemplateDelete struct {
+			DeletedPipelineTemplateID graphql.String `graphql:"deletedPipelineTemplateId"`
+		} `graphql:"pipelineTemplateDelete(input: $input)"`
+	}
+	type PipelineTemplateDeleteInput struct {
+		OrganizationID string `json:"organizationId"`
+		ID             string `json:"id"`
+	}
+	vars := map[string]interface{}{
+		"input": PipelineTemplateDeleteInput{
+			OrganizationID: c.orgID,
+			ID:             string(template.ID),
+		},
+	}
+	return c.gqlClient.Mutate(context.TODO(), &mutation, vars)
+}
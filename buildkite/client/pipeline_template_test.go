@@ -0,0 +1,52 @@
+package client
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/shurcooL/graphql"
+)
+
+func TestPipelineTemplateCRUD(t *testing.T) {
+	name := fmt.Sprintf("test-template-%d", rand.Int31n(10000))
+	template := &PipelineTemplate{
+		Name:        graphql.String(name),
+		Description: "integration tests",
+		Configuration: `steps:
+  - command: echo "hello"
+`,
+		Available: false,
+	}
+	if err := cli.CreatePipelineTemplate(template); err != nil {
+		t.Fatalf("Couldn't create pipeline template: %s", err)
+	}
+	if template.ID == "" {
+		t.Errorf("Pipeline template ID was blank")
+	}
+
+	template.Available = true
+	if err := cli.UpdatePipelineTemplate(template); err != nil {
+		t.Errorf("Couldn't update pipeline template: %s", err)
+	}
+
+	updatedTemplate, err := cli.ReadPipelineTemplate(string(template.ID))
+	if err != nil {
+		t.Errorf("Couldn't read pipeline template: %s", err)
+	}
+	if !reflect.DeepEqual(template, updatedTemplate) {
+		t.Errorf("Actual pipeline template not equal to updated pipeline template")
+	}
+
+	if err := cli.DeletePipelineTemplate(template); err != nil {
+		t.Errorf("Couldn't delete pipeline template: %s", err)
+	}
+	template, err = cli.ReadPipelineTemplate(string(template.ID))
+	if err != nil {
+		t.Errorf("Couldn't read pipeline template: %s", err)
+	}
+	if template.ID != "" {
+		t.Error("Expected empty pipeline template struct after delete")
+	}
+}
@@ -39,7 +39,7 @@ steps:
 	}
 
 	// Test create.
-	if err := cli.CreatePipeline(p); err != nil {
+	if err := cli.CreatePipeline(p, nil); err != nil {
 		t.Errorf("Could not create pipeline: %s", err)
 	}
 
@@ -77,3 +77,74 @@ steps:
 		t.Errorf("Could not delete pipeline: %s", err)
 	}
 }
+
+func TestPipelineArchive(t *testing.T) {
+	p, err := setupPipeline()
+	if err != nil {
+		t.Fatalf("Couldn't setup pipeline %s", err)
+	}
+	defer cli.DeletePipeline(p)
+
+	details, err := cli.ReadPipelineDetails(*p.Slug)
+	if err != nil {
+		t.Fatalf("Could not read pipeline details: %s", err)
+	}
+	if details.Archived {
+		t.Errorf("New pipeline was archived")
+	}
+
+	// Test archive.
+	if err := cli.ArchivePipeline(string(details.ID)); err != nil {
+		t.Errorf("Could not archive pipeline: %s", err)
+	}
+	details, err = cli.ReadPipelineDetails(*p.Slug)
+	if err != nil {
+		t.Errorf("Could not read pipeline details: %s", err)
+	}
+	if !details.Archived {
+		t.Errorf("Pipeline was not archived")
+	}
+
+	// Test unarchive.
+	if err := cli.UnarchivePipeline(string(details.ID)); err != nil {
+		t.Errorf("Could not unarchive pipeline: %s", err)
+	}
+	details, err = cli.ReadPipelineDetails(*p.Slug)
+	if err != nil {
+		t.Errorf("Could not read pipeline details: %s", err)
+	}
+	if details.Archived {
+		t.Errorf("Pipeline was still archived")
+	}
+}
+
+func TestPipelineUpdateDetails(t *testing.T) {
+	p, err := setupPipeline()
+	if err != nil {
+		t.Fatalf("Couldn't setup pipeline %s", err)
+	}
+	defer cli.DeletePipeline(p)
+
+	details, err := cli.ReadPipelineDetails(*p.Slug)
+	if err != nil {
+		t.Fatalf("Could not read pipeline details: %s", err)
+	}
+	details.Visibility = "PUBLIC"
+	details.Emoji = ":rocket:"
+	details.Color = "#FF0000"
+	details.Tags = []PipelineTag{{Label: "terraform"}}
+	if err := cli.UpdatePipelineDetails(details); err != nil {
+		t.Fatalf("Could not update pipeline details: %s", err)
+	}
+
+	details, err = cli.ReadPipelineDetails(*p.Slug)
+	if err != nil {
+		t.Fatalf("Could not read pipeline details: %s", err)
+	}
+	if details.Visibility != "PUBLIC" || details.Emoji != ":rocket:" || details.Color != "#FF0000" {
+		t.Errorf("Pipeline details were not updated: %+v", details)
+	}
+	if len(details.Tags) != 1 || details.Tags[0].Label != "terraform" {
+		t.Errorf("Pipeline tags were not updated: %+v", details.Tags)
+	}
+}
@@ -10,6 +10,7 @@ import (
 // Team represents a Buildkite team.
 type Team struct {
 	ID                graphql.String
+	UUID              graphql.String
 	Name              graphql.String
 	Privacy           graphql.String
 	IsDefaultTeam     graphql.Boolean
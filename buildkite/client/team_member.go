@@ -20,7 +20,7 @@ func (c *Client) CreateTeamMember(member *TeamMember) error {
 				Node struct {
 					ID graphql.String `graphql:"id"`
 				} `graphql:"node"`
-			} `graphql:"teamMemberEdge`
+			} `graphql:"teamMemberEdge"`
 		} `graphql:"teamMemberCreate(input: $input)"`
 	}
 	type TeamMemberCreateInput struct {
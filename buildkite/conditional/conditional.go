@@ -0,0 +1,280 @@
+// Package conditional parses Buildkite's conditionals expression language, as used by step `if`
+// attributes and the `filter_condition` provider setting, e.g.
+//
+//	build.branch == "main" && build.message !~ /skip ci/i
+package conditional
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a 1-based line and column in an expression.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Error describes a syntax error in an expression.
+type Error struct {
+	Position
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// Expr is a node of a parsed expression. String renders it fully parenthesized.
+type Expr interface {
+	Pos() Position
+	String() string
+}
+
+// Literal is a string, number, regular expression, boolean or null.
+type Literal struct {
+	Position
+	Value string
+}
+
+func (l *Literal) Pos() Position  { return l.Position }
+func (l *Literal) String() string { return l.Value }
+
+// Variable is a dotted variable, e.g. build.branch.
+type Variable struct {
+	Position
+	Name string
+}
+
+func (v *Variable) Pos() Position  { return v.Position }
+func (v *Variable) String() string { return v.Name }
+
+// Call is a function call, e.g. build.env("DEPLOY").
+type Call struct {
+	Position
+	Name string
+	Args []Expr
+}
+
+func (c *Call) Pos() Position { return c.Position }
+func (c *Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(args, ", "))
+}
+
+// Unary is a negated expression.
+type Unary struct {
+	Position
+	Op string
+	X  Expr
+}
+
+func (u *Unary) Pos() Position  { return u.Position }
+func (u *Unary) String() string { return fmt.Sprintf("(%s%s)", u.Op, u.X) }
+
+// Binary is a comparison or logical operation.
+type Binary struct {
+	Position
+	Op          string
+	Left, Right Expr
+}
+
+func (b *Binary) Pos() Position  { return b.Position }
+func (b *Binary) String() string { return fmt.Sprintf("(%s %s %s)", b.Left, b.Op, b.Right) }
+
+// Parse parses an expression. Errors are returned as *Error.
+func Parse(expr string) (Expr, error) {
+	p := &parser{lex: newLexer(expr)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return nil, p.errorf("expression is empty")
+	}
+	x, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %s, expected an operator", p.tok)
+	}
+	return x, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	return p.lex.errorf(p.tok.pos, format, args...)
+}
+
+func (p *parser) is(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+// or := and ("||" and)*
+func (p *parser) or() (Expr, error) {
+	return p.binary(p.and, "||")
+}
+
+// and := not ("&&" not)*
+func (p *parser) and() (Expr, error) {
+	return p.binary(p.not, "&&")
+}
+
+func (p *parser) binary(operand func() (Expr, error), op string) (Expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(tokenOperator, op) {
+		pos := p.tok.pos
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Position: pos, Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// not := "!" not | comparison
+func (p *parser) not() (Expr, error) {
+	if !p.is(tokenOperator, "!") {
+		return p.comparison()
+	}
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	x, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	return &Unary{Position: pos, Op: "!", X: x}, nil
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "=~": true, "!~": true, "includes": true}
+
+// comparison := operand (("==" | "!=" | "=~" | "!~" | "includes") operand)?
+func (p *parser) comparison() (Expr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if !(p.tok.kind == tokenOperator || p.tok.kind == tokenIdent) || !comparisons[p.tok.value] {
+		return left, nil
+	}
+	op, pos := p.tok.value, p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if (op == "=~" || op == "!~") && !isRegexp(right) {
+		return nil, &Error{Position: right.Pos(), Message: fmt.Sprintf("%s must be followed by a regular expression, e.g. /^main$/", op)}
+	}
+	if comparisons[p.tok.value] && (p.tok.kind == tokenOperator || p.tok.kind == tokenIdent) {
+		return nil, p.errorf("comparisons can't be chained, use && or parentheses")
+	}
+	return &Binary{Position: pos, Op: op, Left: left, Right: right}, nil
+}
+
+func isRegexp(x Expr) bool {
+	lit, ok := x.(*Literal)
+	return ok && strings.HasPrefix(lit.Value, "/")
+}
+
+// operand := literal | variable | call | "(" or ")"
+func (p *parser) operand() (Expr, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenString, tokenNumber, tokenRegexp:
+		return &Literal{Position: tok.pos, Value: tok.value}, p.advance()
+	case tokenIdent:
+		return p.variable()
+	case tokenPunct:
+		if tok.value == "(" {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if !p.is(tokenPunct, ")") {
+				return nil, p.errorf("unexpected %s, expected \")\" to close the \"(\" at line %d, column %d", p.tok, tok.pos.Line, tok.pos.Column)
+			}
+			return x, p.advance()
+		}
+	}
+	return nil, p.errorf("unexpected %s, expected a value", tok)
+}
+
+// variable := ident ("." ident)* ("(" (or ("," or)*)? ")")?
+func (p *parser) variable() (Expr, error) {
+	pos, name := p.tok.pos, p.tok.value
+	switch name {
+	case "true", "false", "null":
+		return &Literal{Position: pos, Value: name}, p.advance()
+	case "includes":
+		return nil, p.errorf("unexpected \"includes\", expected a value")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	for p.is(tokenPunct, ".") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenIdent {
+			return nil, p.errorf("unexpected %s, expected a name after \".\"", p.tok)
+		}
+		name += "." + p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if !p.is(tokenPunct, "(") {
+		return &Variable{Position: pos, Name: name}, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	call := &Call{Position: pos, Name: name}
+	for !p.is(tokenPunct, ")") {
+		if len(call.Args) > 0 {
+			if !p.is(tokenPunct, ",") {
+				return nil, p.errorf("unexpected %s, expected \",\" or \")\"", p.tok)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+	}
+	return call, p.advance()
+}
@@ -0,0 +1,66 @@
+package conditional
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: `build.branch == "main"`, expected: `(build.branch == "main")`},
+		{expr: `build.branch == 'main'`, expected: `(build.branch == 'main')`},
+		{expr: `build.tag != null`, expected: `(build.tag != null)`},
+		{expr: `build.message !~ /skip ci/i`, expected: `(build.message !~ /skip ci/i)`},
+		{expr: `build.branch =~ /^release\/v[0-9]+$/`, expected: `(build.branch =~ /^release\/v[0-9]+$/)`},
+		{expr: `build.pull_request.draft == false`, expected: `(build.pull_request.draft == false)`},
+		{expr: `build.pull_request.labels includes "deploy"`, expected: `(build.pull_request.labels includes "deploy")`},
+		{expr: `build.env("DEPLOY") == "1"`, expected: `(build.env("DEPLOY") == "1")`},
+		{expr: `!build.pull_request.draft`, expected: `(!build.pull_request.draft)`},
+		{expr: `a || b && c`, expected: `(a || (b && c))`},
+		{expr: `(a || b) && c`, expected: `((a || b) && c)`},
+		{expr: `a && b && c`, expected: `((a && b) && c)`},
+		{expr: `!(a == 1) || b`, expected: `((!(a == 1)) || b)`},
+		{expr: "build.branch == \"main\"\n  && build.source != \"schedule\"", expected: `((build.branch == "main") && (build.source != "schedule"))`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			x, err := Parse(tc.expr)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, x.String())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: ``, expected: `line 1, column 1: expression is empty`},
+		{expr: `build.branch = "main"`, expected: `line 1, column 14: unexpected '=', use == to compare values`},
+		{expr: `a & b`, expected: `line 1, column 3: unexpected '&', use &&`},
+		{expr: `build.branch == "main`, expected: `line 1, column 17: unterminated string`},
+		{expr: `build.message =~ /skip`, expected: `line 1, column 18: unterminated regular expression`},
+		{expr: `build.message =~ "skip"`, expected: `line 1, column 18: =~ must be followed by a regular expression, e.g. /^main$/`},
+		{expr: `build.branch == `, expected: `line 1, column 17: unexpected end of expression, expected a value`},
+		{expr: `build.branch "main"`, expected: `line 1, column 14: unexpected "main", expected an operator`},
+		{expr: `(a || b`, expected: `line 1, column 8: unexpected end of expression, expected ")" to close the "(" at line 1, column 1`},
+		{expr: `a == b == c`, expected: `line 1, column 8: comparisons can't be chained, use && or parentheses`},
+		{expr: `build. == 1`, expected: `line 1, column 8: unexpected "==", expected a name after "."`},
+		{expr: `build.env("A" "B")`, expected: `line 1, column 15: unexpected "B", expected "," or ")"`},
+		{expr: "a == 1 &&\n  b == #", expected: `line 2, column 8: unexpected '#'`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := Parse(tc.expr)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package conditional
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenRegexp
+	tokenOperator
+	tokenPunct
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of expression"
+	case tokenIdent:
+		return "identifier"
+	case tokenString:
+		return "string"
+	case tokenNumber:
+		return "number"
+	case tokenRegexp:
+		return "regular expression"
+	}
+	return "operator"
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   Position
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return t.kind.String()
+	case tokenString, tokenRegexp:
+		return t.value
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// operators are ordered so that longer operators are matched first.
+var operators = []string{"==", "!=", "=~", "!~", "&&", "||", "!"}
+
+type lexer struct {
+	input []rune
+	i     int
+	pos   Position
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: []rune(input), pos: Position{Line: 1, Column: 1}}
+}
+
+func (l *lexer) peek(offset int) rune {
+	if l.i+offset >= len(l.input) {
+		return 0
+	}
+	return l.input[l.i+offset]
+}
+
+func (l *lexer) advance() rune {
+	r := l.input[l.i]
+	l.i++
+	if r == '\n' {
+		l.pos.Line++
+		l.pos.Column = 1
+	} else {
+		l.pos.Column++
+	}
+	return r
+}
+
+func (l *lexer) errorf(pos Position, format string, args ...interface{}) *Error {
+	return &Error{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// next returns the next token in the input.
+func (l *lexer) next() (token, error) {
+	for l.i < len(l.input) && unicode.IsSpace(l.peek(0)) {
+		l.advance()
+	}
+	start := l.pos
+	if l.i >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	r := l.peek(0)
+	switch {
+	case r == '_' || unicode.IsLetter(r):
+		var b strings.Builder
+		for r := l.peek(0); r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r); r = l.peek(0) {
+			b.WriteRune(l.advance())
+		}
+		return token{kind: tokenIdent, value: b.String(), pos: start}, nil
+	case unicode.IsDigit(r):
+		var b strings.Builder
+		for unicode.IsDigit(l.peek(0)) {
+			b.WriteRune(l.advance())
+		}
+		return token{kind: tokenNumber, value: b.String(), pos: start}, nil
+	case r == '"' || r == '\'':
+		return l.quoted(tokenString, "string")
+	case r == '/':
+		tok, err := l.quoted(tokenRegexp, "regular expression")
+		if err != nil {
+			return tok, err
+		}
+		// Flags, e.g. /main/i.
+		for unicode.IsLetter(l.peek(0)) {
+			tok.value += string(l.advance())
+		}
+		return tok, nil
+	case strings.ContainsRune("().,", r):
+		l.advance()
+		return token{kind: tokenPunct, value: string(r), pos: start}, nil
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(string(l.input[l.i:]), op) {
+			for range op {
+				l.advance()
+			}
+			return token{kind: tokenOperator, value: op, pos: start}, nil
+		}
+	}
+	if r == '=' {
+		return token{}, l.errorf(start, "unexpected %q, use == to compare values", r)
+	}
+	if r == '&' || r == '|' {
+		return token{}, l.errorf(start, "unexpected %q, use %c%c", r, r, r)
+	}
+	return token{}, l.errorf(start, "unexpected %q", r)
+}
+
+// quoted reads a string or regular expression delimited by its first character. Backslashes escape
+// the following character.
+func (l *lexer) quoted(kind tokenKind, name string) (token, error) {
+	start := l.pos
+	quote := l.advance()
+	var b strings.Builder
+	b.WriteRune(quote)
+	for {
+		if l.i >= len(l.input) || l.peek(0) == '\n' {
+			return token{}, l.errorf(start, "unterminated %s", name)
+		}
+		r := l.advance()
+		b.WriteRune(r)
+		if r == '\\' && l.i < len(l.input) {
+			b.WriteRune(l.advance())
+			continue
+		}
+		if r == quote {
+			return token{kind: kind, value: b.String(), pos: start}, nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package buildkite
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/branchfilter"
+)
+
+func dataSourceBranchFilterMatch() *schema.Resource {
+	return &schema.Resource{
+		Description: "A data source to check which branches a branch filter pattern accepts, e.g. to test filters before using them in a pipeline.",
+		Schema: map[string]*schema.Schema{
+			"pattern": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateBranchFilter,
+			},
+			"branches": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"matching_branches": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The branches the pattern accepts, in the order they were given.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"rejected_branches": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The branches the pattern rejects, in the order they were given.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: getBranchFilterMatch,
+	}
+}
+
+func getBranchFilterMatch(d *schema.ResourceData, m interface{}) error {
+	pattern := d.Get("pattern").(string)
+	filter, err := branchfilter.Parse(pattern)
+	if err != nil {
+		return err
+	}
+	branches := []string{}
+	matching, rejected := []interface{}{}, []interface{}{}
+	for _, b := range d.Get("branches").([]interface{}) {
+		branch, _ := b.(string)
+		branches = append(branches, branch)
+		if filter.Match(branch) {
+			matching = append(matching, branch)
+		} else {
+			rejected = append(rejected, branch)
+		}
+	}
+	d.SetId(strconv.Itoa(hashcode.String(pattern + "\n" + strings.Join(branches, "\n"))))
+	if err := d.Set("matching_branches", matching); err != nil {
+		return err
+	}
+	return d.Set("rejected_branches", rejected)
+}
@@ -0,0 +1,44 @@
+package buildkite
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+const testAccBranchFilterMatchConfig = `
+data "buildkite_branch_filter_match" "test" {
+	pattern = "master feature/* !feature/wip-*"
+	branches = ["master", "feature/login", "feature/wip-login", "bugfix/login"]
+}
+`
+
+func TestAccDataSourceBranchFilterMatch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactory,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBranchFilterMatchConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.buildkite_branch_filter_match.test", "matching_branches.#", "2"),
+					resource.TestCheckResourceAttr("data.buildkite_branch_filter_match.test", "matching_branches.0", "master"),
+					resource.TestCheckResourceAttr("data.buildkite_branch_filter_match.test", "matching_branches.1", "feature/login"),
+					resource.TestCheckResourceAttr("data.buildkite_branch_filter_match.test", "rejected_branches.#", "2"),
+				),
+			},
+			{
+				Config: `
+data "buildkite_branch_filter_match" "test" {
+	pattern = "master !"
+	branches = ["master"]
+}
+`,
+				ExpectError: regexp.MustCompile(`"!" has nothing to negate`),
+			},
+		},
+	})
+}
@@ -0,0 +1,122 @@
+package buildkite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+)
+
+func dataSourceBuild() *schema.Resource {
+	return &schema.Resource{
+		Description: "A data source to look up the latest build of a pipeline, optionally on a branch and in a state, or a build by number.",
+		Schema: map[string]*schema.Schema{
+			"pipeline_slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"number": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				Description:   "The number of the build to look up instead of the latest.",
+				ConflictsWith: []string{"branch", "state"},
+			},
+			"branch": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Only consider builds of this branch.",
+			},
+			"state": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Only consider builds in this state, e.g. `passed`. `finished` matches every state of a finished build.",
+			},
+			"commit": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"message": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"web_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the build in the Buildkite UI.",
+			},
+			"created_at": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the build was created, in RFC 3339 format.",
+			},
+			"finished_at": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the build finished, in RFC 3339 format, or empty if it hasn't.",
+			},
+			"meta_data": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: getBuild,
+	}
+}
+
+func getBuild(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	slug := d.Get("pipeline_slug").(string)
+
+	var build *client.Build
+	if number, ok := d.GetOk("number"); ok {
+		b, err := bk.ReadBuild(slug, number.(int))
+		if err != nil {
+			return err
+		}
+		build = b
+	} else {
+		opt := &client.BuildsListOptions{Branch: d.Get("branch").(string)}
+		if state := d.Get("state").(string); state != "" {
+			opt.State = []string{state}
+		}
+		opt.PerPage = 1
+		builds, err := bk.ListBuilds(slug, opt)
+		if err != nil {
+			return err
+		}
+		if len(builds) == 0 {
+			return fmt.Errorf("no builds of pipeline %s match the given branch and state", slug)
+		}
+		build = &builds[0]
+	}
+
+	d.SetId(buildID(slug, *build.Number))
+	d.Set("number", build.Number)
+	d.Set("branch", build.Branch)
+	d.Set("state", build.State)
+	d.Set("commit", build.Commit)
+	d.Set("message", build.Message)
+	d.Set("web_url", build.WebURL)
+	d.Set("created_at", formatTimestamp(build.CreatedAt))
+	d.Set("finished_at", formatTimestamp(build.FinishedAt))
+	meta := map[string]interface{}{}
+	if values, ok := build.MetaData.(map[string]interface{}); ok {
+		for k, v := range values {
+			meta[k] = stringValue(v)
+		}
+	}
+	d.Set("meta_data", meta)
+	return nil
+}
+
+func formatTimestamp(t *client.Timestamp) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
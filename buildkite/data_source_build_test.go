@@ -0,0 +1,56 @@
+package buildkite
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func testAccDataSourceBuildConfig(name string) string {
+	return testAccBuildConfig(name) + `
+data "buildkite_build" "latest" {
+	pipeline_slug = buildkite_build.test.pipeline_slug
+	branch = "master"
+}
+
+data "buildkite_build" "number" {
+	pipeline_slug = buildkite_build.test.pipeline_slug
+	number = buildkite_build.test.number
+}
+`
+}
+
+func TestAccDataSourceBuild(t *testing.T) {
+	rName := acctest.RandString(5)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactory,
+		CheckDestroy:      testAccPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceBuildConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.buildkite_build.latest", "number", "buildkite_build.test", "number"),
+					resource.TestCheckResourceAttrPair("data.buildkite_build.latest", "web_url", "buildkite_build.test", "web_url"),
+					resource.TestCheckResourceAttr("data.buildkite_build.latest", "message", "Seed"),
+					resource.TestCheckResourceAttr("data.buildkite_build.latest", "meta_data.release", "seed"),
+					resource.TestCheckResourceAttrSet("data.buildkite_build.latest", "created_at"),
+					resource.TestCheckResourceAttr("data.buildkite_build.number", "branch", "master"),
+				),
+			},
+			{
+				Config: testAccDataSourceBuildConfig(rName) + `
+data "buildkite_build" "missing" {
+	pipeline_slug = buildkite_build.test.pipeline_slug
+	branch = "does-not-exist"
+}
+`,
+				ExpectError: regexp.MustCompile(`no builds of pipeline .+ match the given branch and state`),
+			},
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package buildkite
+
+import (
+	buildkiteRest "github.com/buildkite/go-buildkite/v2/buildkite"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourcePipeline() *schema.Resource {
+	return &schema.Resource{
+		Description: "A data source to reference pipelines by slug, e.g. pipelines managed by another workspace.",
+		Schema: map[string]*schema.Schema{
+			"slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"uuid": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_branch": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"steps": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"webhook_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository_provider": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"provider_settings": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: getPipeline,
+	}
+}
+
+func getPipeline(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	slug := d.Get("slug").(string)
+	p, err := bk.ReadPipeline(slug)
+	if err != nil {
+		return err
+	}
+	id, err := bk.GetPipelineID(slug)
+	if err != nil {
+		return err
+	}
+	d.SetId(id)
+	d.Set("uuid", p.ID)
+	d.Set("name", p.Name)
+	d.Set("repository", p.Repository)
+	d.Set("default_branch", p.DefaultBranch)
+	d.Set("steps", p.Configuration)
+
+	var settings buildkiteRest.ProviderSettings
+	if p.Provider != nil {
+		settings = p.Provider.Settings
+		d.Set("webhook_url", p.Provider.WebhookURL)
+		d.Set("repository_provider", p.Provider.ID)
+	}
+	return d.Set("provider_settings", providerSettingsMap(settings))
+}
@@ -0,0 +1,42 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func testAccDataSourcePipelineConfig(name string) string {
+	return testAccPipelineConfig(name) + `
+data "buildkite_pipeline" "test" {
+	slug = buildkite_pipeline.test.slug
+}
+`
+}
+
+func TestAccDataSourcePipeline(t *testing.T) {
+	rName := acctest.RandString(5)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactory,
+		CheckDestroy:      testAccPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourcePipelineConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.buildkite_pipeline.test", "id", "buildkite_pipeline.test", "id"),
+					resource.TestCheckResourceAttrPair("data.buildkite_pipeline.test", "uuid", "buildkite_pipeline.test", "uuid"),
+					resource.TestCheckResourceAttrPair("data.buildkite_pipeline.test", "webhook_url", "buildkite_pipeline.test", "webhook_url"),
+					resource.TestCheckResourceAttr("data.buildkite_pipeline.test", "name", rName),
+					resource.TestCheckResourceAttr("data.buildkite_pipeline.test", "repository", repoName),
+					resource.TestCheckResourceAttr("data.buildkite_pipeline.test", "default_branch", "master"),
+					resource.TestCheckResourceAttr("data.buildkite_pipeline.test", "provider_settings.build_pull_requests", "true"),
+					resource.TestCheckResourceAttrSet("data.buildkite_pipeline.test", "steps"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,140 @@
+package buildkite
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+)
+
+func dataSourcePipelines() *schema.Resource {
+	return &schema.Resource{
+		Description: "A data source to list the pipelines in the organization, optionally filtered.",
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list pipelines whose name contains this, ignoring case.",
+			},
+			"repository": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list pipelines building from this repository.",
+			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list pipelines with this tag.",
+			},
+			"cluster_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list pipelines in the cluster with this GraphQL ID.",
+			},
+			"team_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list pipelines the team with this GraphQL ID has access to.",
+			},
+			"pipelines": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"slug": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"repository": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Read: getPipelines,
+	}
+}
+
+// pipelineFilter selects pipelines when listing them. Empty fields match every pipeline.
+type pipelineFilter struct {
+	name       string
+	repository string
+	tag        string
+	clusterID  string
+	teamID     string
+}
+
+func (f pipelineFilter) matches(p client.PipelineSummary) bool {
+	if f.name != "" && !strings.Contains(strings.ToLower(string(p.Name)), strings.ToLower(f.name)) {
+		return false
+	}
+	if f.repository != "" && string(p.Repository.URL) != f.repository {
+		return false
+	}
+	if f.clusterID != "" && string(p.Cluster.ID) != f.clusterID {
+		return false
+	}
+	if f.tag != "" {
+		found := false
+		for _, tag := range p.Tags {
+			found = found || string(tag.Label) == f.tag
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.teamID != "" {
+		found := false
+		for _, edge := range p.Teams.Edges {
+			found = found || string(edge.Node.Team.ID) == f.teamID
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func getPipelines(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	filter := pipelineFilter{
+		name:       d.Get("name").(string),
+		repository: d.Get("repository").(string),
+		tag:        d.Get("tag").(string),
+		clusterID:  d.Get("cluster_id").(string),
+		teamID:     d.Get("team_id").(string),
+	}
+	all, err := bk.ListPipelines(filter.name)
+	if err != nil {
+		return err
+	}
+
+	pipelines := []interface{}{}
+	for _, p := range all {
+		if !filter.matches(p) {
+			continue
+		}
+		pipelines = append(pipelines, map[string]interface{}{
+			"id":         string(p.ID),
+			"slug":       string(p.Slug),
+			"name":       string(p.Name),
+			"repository": string(p.Repository.URL),
+		})
+	}
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join([]string{
+		filter.name, filter.repository, filter.tag, filter.clusterID, filter.teamID,
+	}, "\n"))))
+	return d.Set("pipelines", pipelines)
+}
@@ -0,0 +1,81 @@
+package buildkite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAccDataSourcePipelinesConfig(name string) string {
+	return testAccPipelineConfigAppearance(name, "private", "#FF0000") + fmt.Sprintf(`
+data "buildkite_pipelines" "test" {
+	name = "%s"
+	tag = "terraform"
+	depends_on = [buildkite_pipeline.test]
+}
+`, name)
+}
+
+func TestAccDataSourcePipelines(t *testing.T) {
+	rName := acctest.RandString(5)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactory,
+		CheckDestroy:      testAccPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourcePipelinesConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.buildkite_pipelines.test", "pipelines.#", "1"),
+					resource.TestCheckResourceAttrPair("data.buildkite_pipelines.test", "pipelines.0.id", "buildkite_pipeline.test", "id"),
+					resource.TestCheckResourceAttrPair("data.buildkite_pipelines.test", "pipelines.0.slug", "buildkite_pipeline.test", "slug"),
+					resource.TestCheckResourceAttr("data.buildkite_pipelines.test", "pipelines.0.name", rName),
+					resource.TestCheckResourceAttr("data.buildkite_pipelines.test", "pipelines.0.repository", repoName),
+				),
+			},
+		},
+	})
+}
+
+func TestPipelineFilter(t *testing.T) {
+	p := client.PipelineSummary{
+		Name: "Deploy Frontend",
+		Tags: []client.PipelineTag{{Label: "web"}, {Label: "prod"}},
+	}
+	p.Repository.URL = "git@github.com:org/frontend.git"
+	p.Cluster.ID = "cluster-1"
+	p.Teams.Edges = make([]client.PipelineTeamEdge, 1)
+	p.Teams.Edges[0].Node.Team.ID = "team-1"
+
+	testCases := []struct {
+		description string
+		filter      pipelineFilter
+		expected    bool
+	}{
+		{description: "no filter", filter: pipelineFilter{}, expected: true},
+		{description: "name substring", filter: pipelineFilter{name: "front"}, expected: true},
+		{description: "name ignores case", filter: pipelineFilter{name: "DEPLOY"}, expected: true},
+		{description: "other name", filter: pipelineFilter{name: "backend"}, expected: false},
+		{description: "repository", filter: pipelineFilter{repository: "git@github.com:org/frontend.git"}, expected: true},
+		{description: "other repository", filter: pipelineFilter{repository: "git@github.com:org/backend.git"}, expected: false},
+		{description: "tag", filter: pipelineFilter{tag: "prod"}, expected: true},
+		{description: "other tag", filter: pipelineFilter{tag: "staging"}, expected: false},
+		{description: "cluster", filter: pipelineFilter{clusterID: "cluster-1"}, expected: true},
+		{description: "other cluster", filter: pipelineFilter{clusterID: "cluster-2"}, expected: false},
+		{description: "team", filter: pipelineFilter{teamID: "team-1"}, expected: true},
+		{description: "other team", filter: pipelineFilter{teamID: "team-2"}, expected: false},
+		{description: "all match", filter: pipelineFilter{name: "deploy", tag: "web", teamID: "team-1"}, expected: true},
+		{description: "one does not match", filter: pipelineFilter{name: "deploy", tag: "staging"}, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.filter.matches(p))
+		})
+	}
+}
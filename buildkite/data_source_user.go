@@ -2,7 +2,6 @@ package buildkite
 
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
 )
 
 func dataSourceUser() *schema.Resource {
@@ -32,7 +31,7 @@ func dataSourceUser() *schema.Resource {
 }
 
 func getUser(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	email := d.Get("email").(string)
 	u, err := bk.GetUser(email)
 	if err != nil {
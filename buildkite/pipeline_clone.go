@@ -0,0 +1,122 @@
+package buildkite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+)
+
+// clonePipelineSource copies the configuration, provider settings, timeouts and teams of
+// source_pipeline_slug into the attributes that aren't configured, so that the pipeline is created
+// with them, and records them in cloned_attributes.
+func clonePipelineSource(d *schema.ResourceData, bk *client.Client) error {
+	slug := d.Get("source_pipeline_slug").(string)
+	if slug == "" {
+		return nil
+	}
+	source, err := bk.ReadPipeline(slug)
+	if err != nil {
+		return fmt.Errorf("reading source pipeline %s: %w", slug, err)
+	}
+	details, err := bk.ReadPipelineDetails(slug)
+	if err != nil {
+		return fmt.Errorf("reading source pipeline %s: %w", slug, err)
+	}
+
+	var cloned []interface{}
+	clone := func(attribute string, value interface{}) {
+		if _, ok := d.GetOk(attribute); !ok {
+			d.Set(attribute, value)
+			cloned = append(cloned, attribute)
+		}
+	}
+	_, stepsOk := d.GetOk("steps")
+	_, stepOk := d.GetOk("step")
+	if !stepsOk && !stepOk {
+		// steps is computed, so it keeps the copied configuration anyway.
+		d.Set("steps", configuredSteps(d, source.Configuration))
+		if details.PipelineTemplate.ID != "" {
+			clone("pipeline_template_id", string(details.PipelineTemplate.ID))
+		}
+	}
+	if details.DefaultTimeoutInMinutes != 0 {
+		clone("default_timeout_in_minutes", int(details.DefaultTimeoutInMinutes))
+	}
+	if details.MaximumTimeoutInMinutes != 0 {
+		clone("maximum_timeout_in_minutes", int(details.MaximumTimeoutInMinutes))
+	}
+	// The settings go in the typed block for the source's provider, as the untyped map is sent as
+	// GitHub settings.
+	if source.Provider != nil && !providerSettingsConfigured(d) {
+		if name := providerSettingsBlockFor(source.Provider.Settings); name != "" {
+			clone(name, providerSettingsBlock(name, source.Provider.Settings))
+		}
+	}
+
+	if _, ok := d.GetOk("team"); !ok {
+		teamPipelines, err := bk.ReadTeamPipelines(string(details.ID))
+		if err != nil {
+			return fmt.Errorf("reading teams of source pipeline %s: %w", slug, err)
+		}
+		teams := make([]interface{}, 0, len(teamPipelines))
+		for _, tp := range teamPipelines {
+			teams = append(teams, map[string]interface{}{
+				"team_id":      string(tp.Team.ID),
+				"access_level": string(tp.AccessLevel),
+			})
+		}
+		if len(teams) > 0 {
+			clone("team", teams)
+		}
+	}
+	return d.Set("cloned_attributes", cloned)
+}
+
+// providerSettingsConfigured reports whether provider_settings or any of the typed settings blocks
+// are set.
+func providerSettingsConfigured(d *schema.ResourceData) bool {
+	if _, ok := d.GetOk("provider_settings"); ok {
+		return true
+	}
+	for _, name := range providerSettingsBlocks {
+		if _, ok := d.GetOk(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressClonedDiff keeps the values of cloned_attributes while they aren't configured, rather
+// than removing them on the next apply. Attributes that aren't configured read as their value in
+// the state while planning, so they only change once they are set.
+func suppressClonedDiff(k, old, new string, d *schema.ResourceData) bool {
+	attribute := strings.SplitN(k, ".", 2)[0]
+	return d.Get("cloned_attributes").(*schema.Set).Contains(attribute) && !d.HasChange(attribute)
+}
+
+// diffClonedAttributes drops attributes from cloned_attributes once they are set, so that removing
+// them later isn't suppressed, and drops every attribute once source_pipeline_slug is removed.
+func diffClonedAttributes(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	cloned := d.Get("cloned_attributes").(*schema.Set)
+	if cloned.Len() == 0 {
+		return nil
+	}
+	kept := []interface{}{}
+	if d.Get("source_pipeline_slug").(string) != "" {
+		for _, attribute := range cloned.List() {
+			// Removals of cloned attributes are suppressed, so any change sets them.
+			if !d.HasChange(attribute.(string)) {
+				kept = append(kept, attribute)
+			}
+		}
+	}
+	if len(kept) == cloned.Len() {
+		return nil
+	}
+	return d.SetNew("cloned_attributes", kept)
+}
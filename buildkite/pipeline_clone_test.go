@@ -0,0 +1,158 @@
+package buildkite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineSourceValidation(t *testing.T) {
+	testCases := []struct {
+		description string
+		raw         map[string]interface{}
+		valid       bool
+	}{
+		{
+			description: "steps",
+			raw:         map[string]interface{}{"steps": "steps: []"},
+			valid:       true,
+		},
+		{
+			description: "source pipeline",
+			raw:         map[string]interface{}{"source_pipeline_slug": "golden"},
+			valid:       true,
+		},
+		{
+			description: "source pipeline and steps",
+			raw:         map[string]interface{}{"source_pipeline_slug": "golden", "steps": "steps: []"},
+			valid:       true,
+		},
+		{
+			description: "neither",
+			raw:         map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tc.raw["name"] = "test"
+			tc.raw["repository"] = "git@github.com:buildkite/terraform-provider-buildkite.git"
+			_, errs := resourcePipeline().Validate(terraform.NewResourceConfigRaw(tc.raw))
+			assert.Equal(t, tc.valid, len(errs) == 0, "%v", errs)
+		})
+	}
+}
+
+func TestClonedAttributesDiff(t *testing.T) {
+	hash := schema.HashSchema(&schema.Schema{Type: schema.TypeString})
+	attributes := func(sourcePipelineSlug string, cloned ...string) map[string]string {
+		a := map[string]string{
+			"name":                           "test",
+			"repository":                     "git@github.com:buildkite/terraform-provider-buildkite.git",
+			"steps":                          "steps: []\n",
+			"default_timeout_in_minutes":     "30",
+			"maximum_timeout_in_minutes":     "60",
+			"github_settings.#":              "1",
+			"github_settings.0.trigger_mode": "code",
+			"source_pipeline_slug":           sourcePipelineSlug,
+			"archived":                       "false",
+			"archive_on_destroy":             "false",
+			"deletion_protection":            "false",
+			"cancel_builds_on_destroy":       "false",
+			"allow_experimental_step_keys":   "false",
+			"auto_create_webhook":            "false",
+			"steps_interpolation":            "buildkite",
+			"cloned_attributes.#":            strconv.Itoa(len(cloned)),
+		}
+		for _, attribute := range cloned {
+			a[fmt.Sprintf("cloned_attributes.%d", hash(attribute))] = attribute
+		}
+		return a
+	}
+	allCloned := []string{"default_timeout_in_minutes", "maximum_timeout_in_minutes", "github_settings"}
+	testCases := []struct {
+		description string
+		state       map[string]string
+		raw         map[string]interface{}
+		changed     []string
+		cloned      []string
+	}{
+		{
+			description: "copied values kept",
+			state:       attributes("golden", allCloned...),
+			raw:         map[string]interface{}{"source_pipeline_slug": "golden"},
+			cloned:      allCloned,
+		},
+		{
+			description: "explicit values override",
+			state:       attributes("golden", allCloned...),
+			raw: map[string]interface{}{
+				"source_pipeline_slug":       "golden",
+				"default_timeout_in_minutes": 10,
+			},
+			changed: []string{"default_timeout_in_minutes", "cloned_attributes.#"},
+			cloned:  []string{"maximum_timeout_in_minutes", "github_settings"},
+		},
+		{
+			description: "explicit value removed",
+			state:       attributes("golden", "maximum_timeout_in_minutes", "github_settings"),
+			raw:         map[string]interface{}{"source_pipeline_slug": "golden"},
+			changed:     []string{"default_timeout_in_minutes"},
+			cloned:      []string{"maximum_timeout_in_minutes", "github_settings"},
+		},
+		{
+			description: "source pipeline removed",
+			state:       attributes("golden", allCloned...),
+			raw:         map[string]interface{}{"steps": "steps: []\n"},
+			changed:     []string{"source_pipeline_slug", "cloned_attributes.#"},
+		},
+		{
+			description: "not cloned",
+			state:       attributes(""),
+			raw:         map[string]interface{}{"steps": "steps: []\n"},
+			changed: []string{
+				"default_timeout_in_minutes",
+				"maximum_timeout_in_minutes",
+				"github_settings.#",
+				"github_settings.0.trigger_mode",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tc.raw["name"] = "test"
+			tc.raw["repository"] = "git@github.com:buildkite/terraform-provider-buildkite.git"
+			r := resourcePipeline()
+			state := &terraform.InstanceState{ID: "UGlwZWxpbmUtLS0x", Attributes: tc.state}
+			diff, err := schema.InternalMap(r.Schema).Diff(state, terraform.NewResourceConfigRaw(tc.raw), r.CustomizeDiff, nil, true)
+			assert.NoError(t, err)
+			var changed []string
+			if diff != nil {
+				for k, attr := range diff.Attributes {
+					// Removed elements of cloned_attributes are covered by its count.
+					if strings.HasPrefix(k, "cloned_attributes.") && k != "cloned_attributes.#" {
+						continue
+					}
+					if attr.Old != attr.New || attr.NewRemoved {
+						changed = append(changed, k)
+					}
+				}
+			}
+			assert.ElementsMatch(t, tc.changed, changed)
+
+			var cloned []string
+			for k, v := range state.MergeDiff(diff).Attributes {
+				if strings.HasPrefix(k, "cloned_attributes.") && k != "cloned_attributes.#" {
+					cloned = append(cloned, v)
+				}
+			}
+			assert.ElementsMatch(t, tc.cloned, cloned)
+		})
+	}
+}
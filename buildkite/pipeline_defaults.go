@@ -0,0 +1,296 @@
+package buildkite
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// pipelineDefaults are the attributes merged into the top level of the pipeline configuration, by
+// the configuration key they are written to.
+var pipelineDefaults = map[string]string{
+	"env":            "env",
+	"default_agents": "agents",
+	"notify":         "notify",
+}
+
+// notifyTypes are the kinds of pipeline notification. Exactly one of them is set on each notify
+// block.
+var notifyTypes = []string{"slack", "email", "webhook", "github_commit_status"}
+
+func notifySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Notifications sent when builds finish, merged into the top level `notify` of the configuration. Each block has exactly one of `slack`, `email`, `webhook` or `github_commit_status`.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"slack": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The Slack channel or user to notify, e.g. `#builds`.",
+				},
+				"email": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"webhook": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The URL to send a webhook to.",
+				},
+				"github_commit_status": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"context": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The context of the commit status, which defaults to the pipeline's slug.",
+							},
+						},
+					},
+				},
+				"if": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "A conditional expression that decides whether the notification is sent.",
+					ValidateFunc: validateConditional,
+				},
+			},
+		},
+	}
+}
+
+// encodeYAML renders a YAML node the way pipelines are usually written, with two space indents.
+func encodeYAML(node *yaml.Node) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// parsePipelineMapping parses a pipeline configuration into a mapping. A bare list of steps is
+// put under a steps key, which Buildkite treats the same.
+func parsePipelineMapping(configuration string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(configuration), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return newMapping().node, nil
+	}
+	root := doc.Content[0]
+	switch root.Kind {
+	case yaml.MappingNode:
+		return root, nil
+	case yaml.SequenceNode:
+		pipeline := newMapping()
+		if err := pipeline.set("steps", root); err != nil {
+			return nil, err
+		}
+		return pipeline.node, nil
+	}
+	return nil, fmt.Errorf("the pipeline configuration must be a mapping or a list of steps")
+}
+
+// mappingIndex returns the index of the key in a mapping's content, or -1 if it isn't there.
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// pipelineDefaultsInUse returns the configuration keys of the pipeline defaults that are set.
+func pipelineDefaultsInUse(get func(string) (interface{}, bool)) map[string]string {
+	inUse := map[string]string{}
+	for attribute, key := range pipelineDefaults {
+		if _, ok := get(attribute); ok {
+			inUse[attribute] = key
+		}
+	}
+	return inUse
+}
+
+// checkPipelineDefaults returns an error if steps already set a key that a pipeline default would
+// be merged into, as it isn't clear which should win.
+func checkPipelineDefaults(steps string, inUse map[string]string) error {
+	if len(inUse) == 0 {
+		return nil
+	}
+	pipeline, err := parsePipelineMapping(steps)
+	if err != nil {
+		return err
+	}
+	for attribute, key := range inUse {
+		if mappingIndex(pipeline, key) >= 0 {
+			return fmt.Errorf("%s can't be set when steps already has a top level %s", attribute, key)
+		}
+	}
+	return nil
+}
+
+func validatePipelineDefaults(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("steps") {
+		return nil
+	}
+	for i, raw := range d.Get("notify").([]interface{}) {
+		if _, err := renderNotification(asMap(raw)); err != nil {
+			return fmt.Errorf("notify.%d: %w", i, err)
+		}
+	}
+	return checkPipelineDefaults(d.Get("steps").(string), pipelineDefaultsInUse(d.GetOk))
+}
+
+// pipelineConfiguration returns the configuration to send to Buildkite, which is steps, escaped
+// if steps_interpolation is literal, with the pipeline defaults merged in.
+func pipelineConfiguration(d *schema.ResourceData) (string, error) {
+	steps := uploadedSteps(d)
+	inUse := pipelineDefaultsInUse(d.GetOk)
+	if len(inUse) == 0 {
+		return steps, nil
+	}
+	if err := checkPipelineDefaults(steps, inUse); err != nil {
+		return "", err
+	}
+	pipeline, err := parsePipelineMapping(steps)
+	if err != nil {
+		return "", err
+	}
+
+	defaults := newMapping()
+	if err := defaults.set("env", d.Get("env").(map[string]interface{})); err != nil {
+		return "", err
+	}
+	if err := defaults.set("agents", d.Get("default_agents").(map[string]interface{})); err != nil {
+		return "", err
+	}
+	pipeline.Content = append(defaults.node.Content, pipeline.Content...)
+
+	notify := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, raw := range d.Get("notify").([]interface{}) {
+		n, err := renderNotification(asMap(raw))
+		if err != nil {
+			return "", err
+		}
+		notify.Content = append(notify.Content, n)
+	}
+	if len(notify.Content) > 0 {
+		pipeline.Content = append(pipeline.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "notify"}, notify)
+	}
+	return encodeYAML(pipeline)
+}
+
+func renderNotification(notification map[string]interface{}) (*yaml.Node, error) {
+	n := newMapping()
+	var kinds []string
+	for _, kind := range notifyTypes {
+		switch v := notification[kind].(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+			if err := n.set(kind, v); err != nil {
+				return nil, err
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				continue
+			}
+			status := newMapping()
+			if err := status.set("context", asMap(v[0])["context"]); err != nil {
+				return nil, err
+			}
+			n.node.Content = append(n.node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: kind}, status.node)
+		default:
+			continue
+		}
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) != 1 {
+		return nil, fmt.Errorf("exactly one of slack, email, webhook or github_commit_status must be set, got %d", len(kinds))
+	}
+	if err := n.set("if", notification["if"]); err != nil {
+		return nil, err
+	}
+	return n.node, nil
+}
+
+// readPipelineDefaults sets the pipeline defaults that are in use from the configuration read from
+// Buildkite, and returns the rest of the configuration to compare with steps.
+func readPipelineDefaults(d *schema.ResourceData, configuration string) (string, error) {
+	inUse := pipelineDefaultsInUse(d.GetOk)
+	if len(inUse) == 0 {
+		return configuration, nil
+	}
+	pipeline, err := parsePipelineMapping(configuration)
+	if err != nil {
+		return "", err
+	}
+	var parsed map[string]interface{}
+	if err := pipeline.Decode(&parsed); err != nil {
+		return "", err
+	}
+
+	for attribute, key := range inUse {
+		var value interface{}
+		switch attribute {
+		case "env":
+			value = flattenStringMap(parsed[key])
+		case "default_agents":
+			value = flattenAgents(parsed[key])
+		case "notify":
+			value = flattenNotifications(parsed[key])
+		}
+		if err := d.Set(attribute, value); err != nil {
+			return "", err
+		}
+		if i := mappingIndex(pipeline, key); i >= 0 {
+			pipeline.Content = append(pipeline.Content[:i], pipeline.Content[i+2:]...)
+		}
+	}
+	return encodeYAML(pipeline)
+}
+
+// flattenNotifications reads notifications back into notify blocks. Kinds of notification that
+// can't be represented, such as Slack notifications to several channels, are skipped.
+func flattenNotifications(raw interface{}) []interface{} {
+	result := []interface{}{}
+	for _, n := range asList(raw) {
+		notification := asMap(n)
+		block := map[string]interface{}{
+			"if": stringValue(notification["if"]),
+		}
+		for _, kind := range notifyTypes {
+			v, ok := notification[kind]
+			if !ok {
+				continue
+			}
+			if kind == "github_commit_status" {
+				block[kind] = []interface{}{map[string]interface{}{
+					"context": stringValue(asMap(v)["context"]),
+				}}
+			} else if s, ok := v.(string); ok {
+				block[kind] = s
+			} else {
+				continue
+			}
+			result = append(result, block)
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineConfiguration(t *testing.T) {
+	const steps = `steps:
+  - command: make test
+`
+	raw := map[string]interface{}{
+		"name":           "test",
+		"repository":     repoName,
+		"steps":          steps,
+		"env":            map[string]interface{}{"CI": "true", "GO111MODULE": "on"},
+		"default_agents": map[string]interface{}{"queue": "default"},
+		"notify": []interface{}{
+			map[string]interface{}{"slack": "#builds", "if": `build.state == "failed"`},
+			map[string]interface{}{"email": "dev@example.com"},
+			map[string]interface{}{"github_commit_status": []interface{}{map[string]interface{}{"context": "buildkite/test"}}},
+		},
+	}
+	expected := `env:
+  CI: "true"
+  GO111MODULE: "on"
+agents:
+  queue: default
+steps:
+  - command: make test
+notify:
+  - slack: '#builds'
+    if: build.state == "failed"
+  - email: dev@example.com
+  - github_commit_status:
+      context: buildkite/test
+`
+
+	d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, raw)
+	configuration, err := pipelineConfiguration(d)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, configuration)
+
+	// Reading the configuration back should give the same defaults and steps.
+	read := schema.TestResourceDataRaw(t, resourcePipeline().Schema, raw)
+	rest, err := readPipelineDefaults(read, configuration)
+	assert.NoError(t, err)
+	assert.True(t, stepsEqual(steps, rest), rest)
+	assert.Equal(t, d.Get("env"), read.Get("env"))
+	assert.Equal(t, d.Get("default_agents"), read.Get("default_agents"))
+	assert.Equal(t, d.Get("notify"), read.Get("notify"))
+}
+
+func TestPipelineConfigurationList(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, map[string]interface{}{
+		"steps": "- command: make test\n",
+		"env":   map[string]interface{}{"CI": "true"},
+	})
+	configuration, err := pipelineConfiguration(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "env:\n  CI: \"true\"\nsteps:\n  - command: make test\n", configuration)
+}
+
+func TestPipelineConfigurationInvalid(t *testing.T) {
+	testCases := []struct {
+		description string
+		raw         map[string]interface{}
+	}{
+		{
+			description: "env in steps",
+			raw: map[string]interface{}{
+				"steps": "env: {CI: true}\nsteps:\n  - command: make test\n",
+				"env":   map[string]interface{}{"CI": "true"},
+			},
+		},
+		{
+			description: "notify without a kind",
+			raw: map[string]interface{}{
+				"steps":  "steps:\n  - command: make test\n",
+				"notify": []interface{}{map[string]interface{}{"if": "build.state == \"failed\""}},
+			},
+		},
+		{
+			description: "notify with several kinds",
+			raw: map[string]interface{}{
+				"steps":  "steps:\n  - command: make test\n",
+				"notify": []interface{}{map[string]interface{}{"slack": "#builds", "email": "dev@example.com"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, tc.raw)
+			_, err := pipelineConfiguration(d)
+			assert.Error(t, err)
+		})
+	}
+}
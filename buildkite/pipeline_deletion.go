@@ -0,0 +1,73 @@
+package buildkite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+)
+
+// unfinishedBuildStates are the states of builds that would be killed by deleting their pipeline.
+// Failing and canceling builds still have jobs running.
+var unfinishedBuildStates = []string{"scheduled", "running", "failing", "canceling"}
+
+// stopPipelineBuilds makes sure no builds are running before the pipeline is deleted. Running
+// builds are canceled if cancel_builds_on_destroy is set, otherwise the deletion is refused.
+func stopPipelineBuilds(d *schema.ResourceData, bk *client.Client) error {
+	slug := d.Get("slug").(string)
+	opt := &client.BuildsListOptions{State: unfinishedBuildStates}
+	opt.PerPage = 100
+	builds, err := bk.ListAllBuilds(slug, opt)
+	if err != nil {
+		return err
+	}
+	if len(builds) == 0 {
+		return nil
+	}
+
+	if !d.Get("cancel_builds_on_destroy").(bool) {
+		lines := make([]string, 0, len(builds))
+		for _, b := range builds {
+			lines = append(lines, describeBuild(&b))
+		}
+		return fmt.Errorf("pipeline %s has builds that haven't finished, wait for them or set cancel_builds_on_destroy to cancel them:\n%s", slug, strings.Join(lines, "\n"))
+	}
+	// The builds share the delete timeout rather than each waiting for up to all of it.
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete))
+	for _, b := range builds {
+		// Builds that are already canceling only need to be waited for.
+		if stringPtrValue(b.State) == "canceling" {
+			continue
+		}
+		if err := bk.CancelBuild(slug, *b.Number); err != nil {
+			return fmt.Errorf("canceling build %d: %w", *b.Number, err)
+		}
+	}
+	for _, b := range builds {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("timed out waiting for build %d to be canceled", *b.Number)
+		}
+		if _, err := waitForBuild(bk, slug, *b.Number, remaining); err != nil {
+			return fmt.Errorf("waiting for build %d to be canceled: %w", *b.Number, err)
+		}
+	}
+	return nil
+}
+
+func describeBuild(b *client.Build) string {
+	var number int
+	if b.Number != nil {
+		number = *b.Number
+	}
+	return fmt.Sprintf("#%d %s on %s: %s", number, stringPtrValue(b.State), stringPtrValue(b.Branch), stringPtrValue(b.WebURL))
+}
+
+func stringPtrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
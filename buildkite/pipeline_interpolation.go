@@ -0,0 +1,67 @@
+package buildkite
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Values of steps_interpolation.
+const (
+	stepsInterpolationBuildkite = "buildkite"
+	stepsInterpolationLiteral   = "literal"
+)
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+
+// escapeInterpolation escapes every `$` in a pipeline configuration so that Buildkite uploads it
+// as written, except for references to the given variables, e.g. $BUILDKITE_BRANCH or
+// ${BUILDKITE_BRANCH:-main}.
+func escapeInterpolation(configuration string, keep map[string]bool) string {
+	var b strings.Builder
+	for i := 0; i < len(configuration); i++ {
+		if configuration[i] != '$' {
+			b.WriteByte(configuration[i])
+			continue
+		}
+		rest := strings.TrimPrefix(configuration[i+1:], "{")
+		if name := variableName.FindString(rest); name != "" && keep[name] {
+			b.WriteByte('$')
+			continue
+		}
+		b.WriteString("$$")
+	}
+	return b.String()
+}
+
+// unescapeInterpolation reverses escapeInterpolation.
+func unescapeInterpolation(configuration string) string {
+	return strings.ReplaceAll(configuration, "$$", "$")
+}
+
+// interpolatedVariables returns the variables that stay interpolated in literal steps.
+func interpolatedVariables(d *schema.ResourceData) map[string]bool {
+	keep := map[string]bool{}
+	for _, name := range d.Get("interpolated_variables").(*schema.Set).List() {
+		keep[name.(string)] = true
+	}
+	return keep
+}
+
+// uploadedSteps returns the steps as they should be sent to Buildkite.
+func uploadedSteps(d *schema.ResourceData) string {
+	steps := d.Get("steps").(string)
+	if d.Get("steps_interpolation").(string) == stepsInterpolationLiteral {
+		return escapeInterpolation(steps, interpolatedVariables(d))
+	}
+	return steps
+}
+
+// configuredSteps returns the steps as they are configured, given the steps Buildkite has.
+func configuredSteps(d *schema.ResourceData, steps string) string {
+	if d.Get("steps_interpolation").(string) == stepsInterpolationLiteral {
+		return unescapeInterpolation(steps)
+	}
+	return steps
+}
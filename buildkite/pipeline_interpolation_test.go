@@ -0,0 +1,50 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeInterpolation(t *testing.T) {
+	keep := map[string]bool{"BUILDKITE_BRANCH": true, "DEPLOY_ENV": true}
+	testCases := []struct {
+		configuration string
+		expected      string
+	}{
+		{configuration: `command: make test`, expected: `command: make test`},
+		{configuration: `command: echo $HOME`, expected: `command: echo $$HOME`},
+		{configuration: `command: echo ${HOME} $$`, expected: `command: echo $${HOME} $$$$`},
+		{configuration: `command: echo "$(date)" $1`, expected: `command: echo "$$(date)" $$1`},
+		{configuration: `command: deploy $DEPLOY_ENV`, expected: `command: deploy $DEPLOY_ENV`},
+		{configuration: `command: deploy ${DEPLOY_ENV:-staging}`, expected: `command: deploy ${DEPLOY_ENV:-staging}`},
+		{configuration: `command: deploy $DEPLOY_ENVIRONMENT`, expected: `command: deploy $$DEPLOY_ENVIRONMENT`},
+		{configuration: `branch: $$BUILDKITE_BRANCH`, expected: `branch: $$$BUILDKITE_BRANCH`},
+		{configuration: `command: echo $`, expected: `command: echo $$`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.configuration, func(t *testing.T) {
+			escaped := escapeInterpolation(tc.configuration, keep)
+			assert.Equal(t, tc.expected, escaped)
+			assert.Equal(t, tc.configuration, unescapeInterpolation(escaped))
+		})
+	}
+}
+
+func TestPipelineConfigurationLiteral(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, map[string]interface{}{
+		"steps":                  "steps:\n  - command: echo $HOME $BUILDKITE_BRANCH\n",
+		"env":                    map[string]interface{}{"CI": "$BUILDKITE_AGENT_NAME"},
+		"steps_interpolation":    stepsInterpolationLiteral,
+		"interpolated_variables": []interface{}{"BUILDKITE_BRANCH"},
+	})
+	configuration, err := pipelineConfiguration(d)
+	assert.NoError(t, err)
+	assert.Equal(t, `env:
+  CI: $BUILDKITE_AGENT_NAME
+steps:
+  - command: echo $$HOME $BUILDKITE_BRANCH
+`, configuration)
+}
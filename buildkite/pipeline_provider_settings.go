@@ -0,0 +1,272 @@
+package buildkite
+
+import (
+	"strconv"
+
+	buildkiteRest "github.com/buildkite/go-buildkite/v2/buildkite"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// providerSettingsBlocks are the typed settings blocks on a pipeline, one per repository provider.
+// GitLab has no block of its own, as its only setting is the read-only repository.
+var providerSettingsBlocks = []string{"github_settings", "github_enterprise_settings", "bitbucket_settings"}
+
+// pullRequestSettingsSchema returns the settings shared by GitHub, GitHub Enterprise and Bitbucket.
+func pullRequestSettingsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"build_pull_requests": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"pull_request_branch_filter_enabled": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"pull_request_branch_filter_configuration": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateBranchFilter,
+		},
+		"skip_pull_request_builds_for_existing_commits": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"build_tags": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"publish_commit_status": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"publish_commit_status_per_step": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+	}
+}
+
+func gitHubSettingsSchema() map[string]*schema.Schema {
+	s := pullRequestSettingsSchema()
+	s["trigger_mode"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Description:  "One of `code`, `deployment`, `fork` or `none`.",
+		ValidateFunc: validation.StringInSlice([]string{"code", "deployment", "fork", "none"}, false),
+	}
+	for _, k := range []string{
+		"build_pull_request_forks",
+		"prefix_pull_request_fork_branch_names",
+		"filter_enabled",
+		"separate_pull_request_statuses",
+		"publish_blocked_as_pending",
+	} {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+		}
+	}
+	s["filter_condition"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateConditional,
+	}
+	return s
+}
+
+// providerSettingsBlockSchema wraps the given settings in a single nested block that conflicts
+// with every other way of specifying provider settings.
+func providerSettingsBlockSchema(name, description string, settings map[string]*schema.Schema) *schema.Schema {
+	conflicts := []string{"provider_settings"}
+	for _, block := range providerSettingsBlocks {
+		if block != name {
+			conflicts = append(conflicts, block)
+		}
+	}
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		Description:   description,
+		ConflictsWith: conflicts,
+		Elem: &schema.Resource{
+			Schema: settings,
+		},
+		DiffSuppressFunc: suppressClonedDiff,
+	}
+}
+
+// providerSettingsFromSchema builds the provider settings for whichever typed settings block is
+// set. It returns nil if none are.
+func providerSettingsFromSchema(d *schema.ResourceData) buildkiteRest.ProviderSettings {
+	block := func(name string) (map[string]interface{}, bool) {
+		l, ok := d.Get(name).([]interface{})
+		if !ok || len(l) == 0 || l[0] == nil {
+			return nil, false
+		}
+		return l[0].(map[string]interface{}), true
+	}
+	if s, ok := block("github_settings"); ok {
+		return &buildkiteRest.GitHubSettings{
+			TriggerMode:                             strPtr(s["trigger_mode"]),
+			BuildPullRequests:                       boolPtr(s["build_pull_requests"]),
+			PullRequestBranchFilterEnabled:          boolPtr(s["pull_request_branch_filter_enabled"]),
+			PullRequestBranchFilterConfiguration:    strPtr(s["pull_request_branch_filter_configuration"]),
+			SkipPullRequestBuildsForExistingCommits: boolPtr(s["skip_pull_request_builds_for_existing_commits"]),
+			BuildPullRequestForks:                   boolPtr(s["build_pull_request_forks"]),
+			PrefixPullRequestForkBranchNames:        boolPtr(s["prefix_pull_request_fork_branch_names"]),
+			BuildTags:                               boolPtr(s["build_tags"]),
+			PublishCommitStatus:                     boolPtr(s["publish_commit_status"]),
+			PublishCommitStatusPerStep:              boolPtr(s["publish_commit_status_per_step"]),
+			FilterEnabled:                           boolPtr(s["filter_enabled"]),
+			FilterCondition:                         strPtr(s["filter_condition"]),
+			SeparatePullRequestStatuses:             boolPtr(s["separate_pull_request_statuses"]),
+			PublishBlockedAsPending:                 boolPtr(s["publish_blocked_as_pending"]),
+		}
+	}
+	if s, ok := block("github_enterprise_settings"); ok {
+		return &buildkiteRest.GitHubEnterpriseSettings{
+			BuildPullRequests:                       boolPtr(s["build_pull_requests"]),
+			PullRequestBranchFilterEnabled:          boolPtr(s["pull_request_branch_filter_enabled"]),
+			PullRequestBranchFilterConfiguration:    strPtr(s["pull_request_branch_filter_configuration"]),
+			SkipPullRequestBuildsForExistingCommits: boolPtr(s["skip_pull_request_builds_for_existing_commits"]),
+			BuildTags:                               boolPtr(s["build_tags"]),
+			PublishCommitStatus:                     boolPtr(s["publish_commit_status"]),
+			PublishCommitStatusPerStep:              boolPtr(s["publish_commit_status_per_step"]),
+		}
+	}
+	if s, ok := block("bitbucket_settings"); ok {
+		return &buildkiteRest.BitbucketSettings{
+			BuildPullRequests:                       boolPtr(s["build_pull_requests"]),
+			PullRequestBranchFilterEnabled:          boolPtr(s["pull_request_branch_filter_enabled"]),
+			PullRequestBranchFilterConfiguration:    strPtr(s["pull_request_branch_filter_configuration"]),
+			SkipPullRequestBuildsForExistingCommits: boolPtr(s["skip_pull_request_builds_for_existing_commits"]),
+			BuildTags:                               boolPtr(s["build_tags"]),
+			PublishCommitStatus:                     boolPtr(s["publish_commit_status"]),
+			PublishCommitStatusPerStep:              boolPtr(s["publish_commit_status_per_step"]),
+		}
+	}
+	return nil
+}
+
+// flattenProviderSettings converts the settings returned by Buildkite, whatever their provider, into
+// a map keyed by the setting's name. Unset settings are left out.
+func flattenProviderSettings(settings buildkiteRest.ProviderSettings) map[string]interface{} {
+	result := map[string]interface{}{}
+	setBool := func(k string, b *bool) {
+		if b != nil {
+			result[k] = *b
+		}
+	}
+	setString := func(k string, s *string) {
+		if s != nil && *s != "" {
+			result[k] = *s
+		}
+	}
+
+	switch s := settings.(type) {
+	case *buildkiteRest.GitHubSettings:
+		setString("trigger_mode", s.TriggerMode)
+		setBool("build_pull_requests", s.BuildPullRequests)
+		setBool("pull_request_branch_filter_enabled", s.PullRequestBranchFilterEnabled)
+		setString("pull_request_branch_filter_configuration", s.PullRequestBranchFilterConfiguration)
+		setBool("skip_pull_request_builds_for_existing_commits", s.SkipPullRequestBuildsForExistingCommits)
+		setBool("build_pull_request_forks", s.BuildPullRequestForks)
+		setBool("prefix_pull_request_fork_branch_names", s.PrefixPullRequestForkBranchNames)
+		setBool("build_tags", s.BuildTags)
+		setBool("publish_commit_status", s.PublishCommitStatus)
+		setBool("publish_commit_status_per_step", s.PublishCommitStatusPerStep)
+		setBool("filter_enabled", s.FilterEnabled)
+		setString("filter_condition", s.FilterCondition)
+		setBool("separate_pull_request_statuses", s.SeparatePullRequestStatuses)
+		setBool("publish_blocked_as_pending", s.PublishBlockedAsPending)
+	case *buildkiteRest.GitHubEnterpriseSettings:
+		setBool("build_pull_requests", s.BuildPullRequests)
+		setBool("pull_request_branch_filter_enabled", s.PullRequestBranchFilterEnabled)
+		setString("pull_request_branch_filter_configuration", s.PullRequestBranchFilterConfiguration)
+		setBool("skip_pull_request_builds_for_existing_commits", s.SkipPullRequestBuildsForExistingCommits)
+		setBool("build_tags", s.BuildTags)
+		setBool("publish_commit_status", s.PublishCommitStatus)
+		setBool("publish_commit_status_per_step", s.PublishCommitStatusPerStep)
+	case *buildkiteRest.BitbucketSettings:
+		setBool("build_pull_requests", s.BuildPullRequests)
+		setBool("pull_request_branch_filter_enabled", s.PullRequestBranchFilterEnabled)
+		setString("pull_request_branch_filter_configuration", s.PullRequestBranchFilterConfiguration)
+		setBool("skip_pull_request_builds_for_existing_commits", s.SkipPullRequestBuildsForExistingCommits)
+		setBool("build_tags", s.BuildTags)
+		setBool("publish_commit_status", s.PublishCommitStatus)
+		setBool("publish_commit_status_per_step", s.PublishCommitStatusPerStep)
+	}
+	// GitLab settings, or a provider unknown to go-buildkite, have nothing to manage.
+	return result
+}
+
+// setProviderSettings stores the settings read from Buildkite in whichever typed block is in use,
+// or else in the untyped provider_settings map if it is in use. Otherwise, e.g. when importing,
+// Bitbucket and GitHub Enterprise settings go in their typed block as the map is sent as GitHub
+// settings.
+func setProviderSettings(d *schema.ResourceData, settings buildkiteRest.ProviderSettings) error {
+	name := ""
+	for _, block := range providerSettingsBlocks {
+		if l, ok := d.Get(block).([]interface{}); ok && len(l) > 0 {
+			name = block
+			break
+		}
+	}
+	if _, ok := d.GetOk("provider_settings"); name == "" && !ok {
+		// The map is sent as GitHub settings, so GitHub settings can stay in it.
+		if block := providerSettingsBlockFor(settings); block != "github_settings" {
+			name = block
+		}
+	}
+	if name == "" {
+		return d.Set("provider_settings", providerSettingsMap(settings))
+	}
+	return d.Set(name, providerSettingsBlock(name, settings))
+}
+
+// providerSettingsBlockFor returns the typed block for the settings' provider, or an empty string
+// for providers without one.
+func providerSettingsBlockFor(settings buildkiteRest.ProviderSettings) string {
+	switch settings.(type) {
+	case *buildkiteRest.GitHubSettings:
+		return "github_settings"
+	case *buildkiteRest.GitHubEnterpriseSettings:
+		return "github_enterprise_settings"
+	case *buildkiteRest.BitbucketSettings:
+		return "bitbucket_settings"
+	}
+	return ""
+}
+
+// providerSettingsBlock returns the settings as the value of the named typed block.
+func providerSettingsBlock(name string, settings buildkiteRest.ProviderSettings) []interface{} {
+	flat := flattenProviderSettings(settings)
+	block := map[string]interface{}{}
+	fields := pullRequestSettingsSchema()
+	if name == "github_settings" {
+		fields = gitHubSettingsSchema()
+	}
+	for k := range fields {
+		if v, ok := flat[k]; ok {
+			block[k] = v
+		}
+	}
+	return []interface{}{block}
+}
+
+// providerSettingsMap returns the settings as strings, for the untyped provider_settings map.
+func providerSettingsMap(settings buildkiteRest.ProviderSettings) map[string]interface{} {
+	flat := flattenProviderSettings(settings)
+	provider := make(map[string]interface{}, len(flat))
+	for k, v := range flat {
+		switch val := v.(type) {
+		case bool:
+			provider[k] = strconv.FormatBool(val)
+		case string:
+			provider[k] = val
+		}
+	}
+	return provider
+}
@@ -0,0 +1,856 @@
+package buildkite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// stepTypes are the nested blocks of a step, in the order they are checked when reading steps back
+// from Buildkite. Exactly one of them is set on each step.
+var stepTypes = []string{"group_step", "trigger_step", "block_step", "input_step", "wait_step", "command_step"}
+
+// stepBlocksSchema returns the schema for a list of structured steps. Buildkite doesn't allow groups
+// to be nested so the steps within a group can't have a group_step.
+func stepBlocksSchema(inGroup bool) *schema.Schema {
+	types := map[string]*schema.Schema{
+		"command_step": stepTypeSchema(commandStepSchema()),
+		"wait_step":    stepTypeSchema(waitStepSchema()),
+		"block_step":   stepTypeSchema(blockStepSchema()),
+		"input_step":   stepTypeSchema(inputStepSchema()),
+		"trigger_step": stepTypeSchema(triggerStepSchema()),
+	}
+	s := &schema.Schema{
+		Type:     schema.TypeList,
+		Elem:     &schema.Resource{Schema: types},
+		MinItems: 1,
+	}
+	if inGroup {
+		s.Required = true
+		return s
+	}
+	types["group_step"] = stepTypeSchema(groupStepSchema())
+	s.Optional = true
+	s.Description = "Structured steps, rendered into `steps` as YAML. Each step has exactly one of `command_step`, `wait_step`, `block_step`, `input_step`, `trigger_step` or `group_step`."
+	s.ConflictsWith = []string{"steps"}
+	s.AtLeastOneOf = []string{"steps", "step", "source_pipeline_slug"}
+	return s
+}
+
+func stepTypeSchema(s map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem:     &schema.Resource{Schema: s},
+	}
+}
+
+// commonStepSchema returns the attributes shared by every type of step.
+func commonStepSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"key": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"if": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateConditional,
+		},
+		"depends_on": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"allow_dependency_failure": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+	}
+}
+
+func commandStepSchema() map[string]*schema.Schema {
+	s := commonStepSchema()
+	s["label"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["command"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["agents"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	s["env"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	s["plugin"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"source": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"config": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Description:      "The plugin's configuration as JSON, e.g. from `jsonencode`.",
+					ValidateFunc:     validation.StringIsJSON,
+					DiffSuppressFunc: structure.SuppressJsonDiff,
+				},
+			},
+		},
+	}
+	s["branches"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["artifact_paths"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	s["timeout_in_minutes"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+	}
+	s["parallelism"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+	}
+	s["concurrency"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+	}
+	s["concurrency_group"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["soft_fail"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+	return s
+}
+
+func waitStepSchema() map[string]*schema.Schema {
+	s := commonStepSchema()
+	s["continue_on_failure"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+	return s
+}
+
+func inputStepSchema() map[string]*schema.Schema {
+	s := commonStepSchema()
+	s["label"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["prompt"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["branches"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["field"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "A text field, or a select field if `select` is set.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"key": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"text": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"select": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"hint": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"required": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  true,
+				},
+				"default": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"multiple": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"option": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"label": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"value": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return s
+}
+
+func blockStepSchema() map[string]*schema.Schema {
+	s := inputStepSchema()
+	s["blocked_state"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"passed", "failed", "running"}, false),
+	}
+	return s
+}
+
+func triggerStepSchema() map[string]*schema.Schema {
+	s := commonStepSchema()
+	s["label"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["trigger"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "The slug of the pipeline to trigger.",
+	}
+	s["async"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+	s["branches"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["soft_fail"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+	s["build"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"branch": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"commit": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"message": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"env": {
+					Type:     schema.TypeMap,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+	return s
+}
+
+func groupStepSchema() map[string]*schema.Schema {
+	s := commonStepSchema()
+	s["label"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["step"] = stepBlocksSchema(true)
+	return s
+}
+
+// mapping builds a YAML mapping, keeping keys in the order they are added. Zero values are left
+// out so that rendered steps only contain what was configured.
+type mapping struct {
+	node *yaml.Node
+}
+
+func newMapping() *mapping {
+	return &mapping{node: &yaml.Node{Kind: yaml.MappingNode}}
+}
+
+func (m *mapping) set(key string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	case int:
+		if v == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+	var n yaml.Node
+	if node, ok := value.(*yaml.Node); ok {
+		n = *node
+	} else if err := n.Encode(value); err != nil {
+		return err
+	}
+	m.node.Content = append(m.node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &n)
+	return nil
+}
+
+// renderStepBlocks renders structured steps into a pipeline configuration.
+func renderStepBlocks(steps []interface{}) (string, error) {
+	list, err := renderStepList(steps)
+	if err != nil {
+		return "", err
+	}
+	pipeline := newMapping()
+	if err := pipeline.set("steps", list); err != nil {
+		return "", err
+	}
+	return encodeYAML(pipeline.node)
+}
+
+func renderStepList(steps []interface{}) (*yaml.Node, error) {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for i, raw := range steps {
+		step, err := renderStep(asMap(raw))
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i, err)
+		}
+		list.Content = append(list.Content, step)
+	}
+	return list, nil
+}
+
+func renderStep(step map[string]interface{}) (*yaml.Node, error) {
+	var kind string
+	var s map[string]interface{}
+	for _, t := range stepTypes {
+		l, ok := step[t].([]interface{})
+		if !ok || len(l) == 0 {
+			continue
+		}
+		if kind != "" {
+			return nil, fmt.Errorf("only one of %s and %s can be set", kind, t)
+		}
+		kind = t
+		s = asMap(l[0])
+	}
+
+	m := newMapping()
+	var err error
+	set := func(key string, value interface{}) {
+		if err == nil {
+			err = m.set(key, value)
+		}
+	}
+	switch kind {
+	case "command_step":
+		set("label", s["label"])
+		set("key", s["key"])
+		set("command", s["command"])
+		set("agents", s["agents"])
+		set("env", s["env"])
+		plugins, perr := renderPlugins(asList(s["plugin"]))
+		if perr != nil {
+			return nil, perr
+		}
+		set("plugins", plugins)
+		set("artifact_paths", s["artifact_paths"])
+		set("branches", s["branches"])
+		set("timeout_in_minutes", s["timeout_in_minutes"])
+		set("parallelism", s["parallelism"])
+		set("concurrency", s["concurrency"])
+		set("concurrency_group", s["concurrency_group"])
+		set("soft_fail", s["soft_fail"])
+	case "wait_step":
+		// A wait step is identified by its wait key, which has no value.
+		set("wait", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "~"})
+		set("key", s["key"])
+		set("continue_on_failure", s["continue_on_failure"])
+	case "block_step", "input_step":
+		set(strings.TrimSuffix(kind, "_step"), s["label"])
+		set("key", s["key"])
+		set("prompt", s["prompt"])
+		set("fields", renderFields(asList(s["field"])))
+		set("branches", s["branches"])
+		set("blocked_state", s["blocked_state"])
+	case "trigger_step":
+		set("label", s["label"])
+		set("key", s["key"])
+		set("trigger", s["trigger"])
+		set("async", s["async"])
+		if build := asList(s["build"]); len(build) > 0 {
+			b := asMap(build[0])
+			bm := newMapping()
+			for _, k := range []string{"branch", "commit", "message", "env"} {
+				if err := bm.set(k, b[k]); err != nil {
+					return nil, err
+				}
+			}
+			set("build", bm.node)
+		}
+		set("branches", s["branches"])
+		set("soft_fail", s["soft_fail"])
+	case "group_step":
+		set("group", s["label"])
+		set("key", s["key"])
+		steps, serr := renderStepList(asList(s["step"]))
+		if serr != nil {
+			return nil, serr
+		}
+		set("steps", steps)
+	default:
+		return nil, errors.New("one of command_step, wait_step, block_step, input_step, trigger_step or group_step must be set")
+	}
+	set("depends_on", s["depends_on"])
+	set("allow_dependency_failure", s["allow_dependency_failure"])
+	set("if", s["if"])
+	return m.node, err
+}
+
+func renderPlugins(plugins []interface{}) (*yaml.Node, error) {
+	if len(plugins) == 0 {
+		return nil, nil
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, raw := range plugins {
+		p := asMap(raw)
+		source, _ := p["source"].(string)
+		config, _ := p["config"].(string)
+		if config == "" {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: source})
+			continue
+		}
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+			return nil, fmt.Errorf("parsing config of plugin %s: %w", source, err)
+		}
+		m := newMapping()
+		var n yaml.Node
+		if err := n.Encode(parsed); err != nil {
+			return nil, err
+		}
+		if err := m.set(source, &n); err != nil {
+			return nil, err
+		}
+		list.Content = append(list.Content, m.node)
+	}
+	return list, nil
+}
+
+func renderFields(fields []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(fields))
+	for _, raw := range fields {
+		f := asMap(raw)
+		field := map[string]interface{}{
+			"key": f["key"],
+		}
+		if s, _ := f["select"].(string); s != "" {
+			field["select"] = s
+			options := []interface{}{}
+			for _, o := range asList(f["option"]) {
+				options = append(options, asMap(o))
+			}
+			field["options"] = options
+			if multiple, _ := f["multiple"].(bool); multiple {
+				field["multiple"] = true
+			}
+		} else if t, _ := f["text"].(string); t != "" {
+			field["text"] = t
+		}
+		for _, k := range []string{"hint", "default"} {
+			if v, _ := f[k].(string); v != "" {
+				field[k] = v
+			}
+		}
+		// Fields are required unless specified otherwise.
+		if required, ok := f["required"].(bool); ok && !required {
+			field["required"] = false
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// flattenStepBlocks parses a pipeline configuration back into structured steps so that changes
+// made outside of Terraform show up as a diff.
+func flattenStepBlocks(configuration string) ([]interface{}, error) {
+	parsed, err := parseSteps(configuration)
+	if err != nil {
+		return nil, err
+	}
+	// The steps are either at the top level or under a steps key.
+	if m, ok := parsed.(map[string]interface{}); ok {
+		parsed = m["steps"]
+	}
+	return flattenStepList(asList(parsed)), nil
+}
+
+func flattenStepList(steps []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(steps))
+	for _, raw := range steps {
+		kind, s := stepType(raw)
+		step := map[string]interface{}{
+			"key":                      stringValue(s["key"]),
+			"if":                       stringValue(s["if"]),
+			"depends_on":               flattenDependsOn(s["depends_on"]),
+			"allow_dependency_failure": boolValue(s["allow_dependency_failure"]),
+		}
+		switch kind {
+		case "command_step":
+			step["label"] = stringValue(firstOf(s, "label", "name"))
+			step["command"] = joinCommands(firstOf(s, "command", "commands"))
+			step["agents"] = flattenAgents(s["agents"])
+			step["env"] = flattenStringMap(s["env"])
+			step["plugin"] = flattenPlugins(s["plugins"])
+			step["artifact_paths"] = flattenStrings(s["artifact_paths"])
+			step["branches"] = stringValue(s["branches"])
+			step["timeout_in_minutes"] = intValue(s["timeout_in_minutes"])
+			step["parallelism"] = intValue(s["parallelism"])
+			step["concurrency"] = intValue(s["concurrency"])
+			step["concurrency_group"] = stringValue(s["concurrency_group"])
+			step["soft_fail"] = boolValue(s["soft_fail"])
+		case "wait_step":
+			step["continue_on_failure"] = boolValue(s["continue_on_failure"])
+		case "block_step", "input_step":
+			step["label"] = stringValue(firstOf(s, strings.TrimSuffix(kind, "_step"), "label", "name"))
+			step["prompt"] = stringValue(s["prompt"])
+			step["field"] = flattenFields(s["fields"])
+			step["branches"] = stringValue(s["branches"])
+			if kind == "block_step" {
+				step["blocked_state"] = stringValue(s["blocked_state"])
+			}
+		case "trigger_step":
+			step["label"] = stringValue(firstOf(s, "label", "name"))
+			step["trigger"] = stringValue(s["trigger"])
+			step["async"] = boolValue(s["async"])
+			step["branches"] = stringValue(s["branches"])
+			step["soft_fail"] = boolValue(s["soft_fail"])
+			if b, ok := s["build"].(map[string]interface{}); ok {
+				step["build"] = []interface{}{map[string]interface{}{
+					"branch":  stringValue(b["branch"]),
+					"commit":  stringValue(b["commit"]),
+					"message": stringValue(b["message"]),
+					"env":     flattenStringMap(b["env"]),
+				}}
+			}
+		case "group_step":
+			step["label"] = stringValue(firstOf(s, "group", "label", "name"))
+			step["step"] = flattenStepList(asList(s["steps"]))
+		}
+		result = append(result, map[string]interface{}{kind: []interface{}{step}})
+	}
+	return result
+}
+
+// stepType determines what type of step a parsed step is, following the same rules as Buildkite.
+func stepType(raw interface{}) (string, map[string]interface{}) {
+	if s, ok := raw.(string); ok {
+		switch s {
+		case "wait", "waiter":
+			return "wait_step", map[string]interface{}{}
+		case "block", "manual":
+			return "block_step", map[string]interface{}{}
+		case "input":
+			return "input_step", map[string]interface{}{}
+		}
+	}
+	s := asMap(raw)
+	switch stringValue(s["type"]) {
+	case "wait", "waiter":
+		return "wait_step", s
+	case "block", "manual":
+		return "block_step", s
+	case "input":
+		return "input_step", s
+	case "trigger":
+		return "trigger_step", s
+	}
+	for _, k := range []string{"group", "trigger", "block", "input", "wait", "waiter"} {
+		if _, ok := s[k]; ok {
+			switch k {
+			case "waiter":
+				return "wait_step", s
+			default:
+				return k + "_step", s
+			}
+		}
+	}
+	return "command_step", s
+}
+
+func flattenPlugins(raw interface{}) []interface{} {
+	var result []interface{}
+	add := func(source string, config interface{}) {
+		plugin := map[string]interface{}{"source": source, "config": ""}
+		if config != nil {
+			b, _ := json.Marshal(config)
+			plugin["config"] = string(b)
+		}
+		result = append(result, plugin)
+	}
+	switch v := raw.(type) {
+	case []interface{}:
+		for _, p := range v {
+			if source, ok := p.(string); ok {
+				add(source, nil)
+				continue
+			}
+			for source, config := range asMap(p) {
+				add(source, config)
+			}
+		}
+	case map[string]interface{}:
+		sources := make([]string, 0, len(v))
+		for source := range v {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			add(source, v[source])
+		}
+	}
+	return result
+}
+
+func flattenFields(raw interface{}) []interface{} {
+	var result []interface{}
+	for _, f := range asList(raw) {
+		fm := asMap(f)
+		field := map[string]interface{}{
+			"key":      stringValue(fm["key"]),
+			"text":     stringValue(fm["text"]),
+			"select":   stringValue(fm["select"]),
+			"hint":     stringValue(fm["hint"]),
+			"default":  stringValue(fm["default"]),
+			"multiple": boolValue(fm["multiple"]),
+			"required": true,
+		}
+		if required, ok := fm["required"].(bool); ok {
+			field["required"] = required
+		}
+		var options []interface{}
+		for _, o := range asList(fm["options"]) {
+			om := asMap(o)
+			options = append(options, map[string]interface{}{
+				"label": stringValue(om["label"]),
+				"value": stringValue(om["value"]),
+			})
+		}
+		field["option"] = options
+		result = append(result, field)
+	}
+	return result
+}
+
+// flattenDependsOn returns the keys of the steps depended on, which can be given as a single key,
+// a list of keys or a list of objects with a step key.
+func flattenDependsOn(raw interface{}) []interface{} {
+	if s, ok := raw.(string); ok {
+		return []interface{}{s}
+	}
+	var result []interface{}
+	for _, d := range asList(raw) {
+		if s, ok := d.(string); ok {
+			result = append(result, s)
+			continue
+		}
+		result = append(result, stringValue(asMap(d)["step"]))
+	}
+	return result
+}
+
+// flattenAgents returns agent tags, which can be given as a map or a list of key=value strings.
+func flattenAgents(raw interface{}) map[string]interface{} {
+	if l, ok := raw.([]interface{}); ok {
+		result := map[string]interface{}{}
+		for _, tag := range l {
+			parts := strings.SplitN(stringValue(tag), "=", 2)
+			if len(parts) == 2 {
+				result[parts[0]] = parts[1]
+			}
+		}
+		return result
+	}
+	return flattenStringMap(raw)
+}
+
+func flattenStringMap(raw interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range asMap(raw) {
+		result[k] = stringValue(v)
+	}
+	return result
+}
+
+func flattenStrings(raw interface{}) []interface{} {
+	if s, ok := raw.(string); ok {
+		return []interface{}{s}
+	}
+	var result []interface{}
+	for _, v := range asList(raw) {
+		result = append(result, stringValue(v))
+	}
+	return result
+}
+
+func joinCommands(raw interface{}) string {
+	if s, ok := raw.(string); ok {
+		return s
+	}
+	var commands []string
+	for _, c := range asList(raw) {
+		commands = append(commands, stringValue(c))
+	}
+	return strings.Join(commands, "\n")
+}
+
+func firstOf(m map[string]interface{}, keys ...string) interface{} {
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func asMap(raw interface{}) map[string]interface{} {
+	if m, ok := raw.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func asList(raw interface{}) []interface{} {
+	if l, ok := raw.([]interface{}); ok {
+		return l
+	}
+	return nil
+}
+
+func stringValue(raw interface{}) string {
+	switch v := raw.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func boolValue(raw interface{}) bool {
+	b, _ := raw.(bool)
+	return b
+}
+
+func intValue(raw interface{}) int {
+	i, _ := raw.(int)
+	return i
+}
+
+// stepBlocksKnown reports whether every value below key is known, as steps can only be rendered
+// once they are.
+func stepBlocksKnown(d *schema.ResourceDiff, key string, value interface{}) bool {
+	if !d.NewValueKnown(key) {
+		return false
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		for i, e := range v {
+			if !stepBlocksKnown(d, fmt.Sprintf("%s.%d", key, i), e) {
+				return false
+			}
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			if !stepBlocksKnown(d, fmt.Sprintf("%s.%s", key, k), e) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// diffStepBlocks renders the structured steps into steps during plan, so that the YAML that will
+// be sent to Buildkite is shown and can be validated.
+func diffStepBlocks(d *schema.ResourceDiff, m interface{}) error {
+	blocks, ok := d.GetOk("step")
+	if !ok {
+		return nil
+	}
+	if !stepBlocksKnown(d, "step", blocks) {
+		return d.SetNewComputed("steps")
+	}
+	steps, err := renderStepBlocks(blocks.([]interface{}))
+	if err != nil {
+		return err
+	}
+	if old, _ := d.GetChange("steps"); stepsEqual(old.(string), steps) {
+		return nil
+	}
+	return d.SetNew("steps", steps)
+}
+
+// setRenderedSteps renders the structured steps into steps, if they are used.
+func setRenderedSteps(d *schema.ResourceData) error {
+	blocks, ok := d.GetOk("step")
+	if !ok {
+		return nil
+	}
+	steps, err := renderStepBlocks(blocks.([]interface{}))
+	if err != nil {
+		return err
+	}
+	return d.Set("steps", steps)
+}
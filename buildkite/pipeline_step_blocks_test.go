@@ -0,0 +1,166 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/pipelineschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderStepBlocks(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":       "test",
+		"repository": repoName,
+		"step": []interface{}{
+			map[string]interface{}{
+				"command_step": []interface{}{map[string]interface{}{
+					"label":   "test things",
+					"key":     "test",
+					"command": "make test",
+					"agents":  map[string]interface{}{"queue": "test"},
+					"plugin": []interface{}{
+						map[string]interface{}{"source": "docker#v3.7.0", "config": `{"image":"golang"}`},
+						map[string]interface{}{"source": "cache#v1.0.0"},
+					},
+					"timeout_in_minutes": 10,
+				}},
+			},
+			map[string]interface{}{
+				"wait_step": []interface{}{map[string]interface{}{"continue_on_failure": true}},
+			},
+			map[string]interface{}{
+				"block_step": []interface{}{map[string]interface{}{
+					"label": "Release",
+					"field": []interface{}{
+						map[string]interface{}{
+							"key":    "stream",
+							"select": "Stream",
+							"option": []interface{}{
+								map[string]interface{}{"label": "Beta", "value": "beta"},
+							},
+						},
+						map[string]interface{}{
+							"key":      "notes",
+							"text":     "Release notes",
+							"required": false,
+						},
+					},
+				}},
+			},
+			map[string]interface{}{
+				"group_step": []interface{}{map[string]interface{}{
+					"label":      "Deploy",
+					"depends_on": []interface{}{"test"},
+					"if":         `build.branch == "master"`,
+					"step": []interface{}{
+						map[string]interface{}{
+							"trigger_step": []interface{}{map[string]interface{}{
+								"trigger": "deploy",
+								"async":   true,
+								"build": []interface{}{map[string]interface{}{
+									"branch": "master",
+								}},
+							}},
+						},
+					},
+				}},
+			},
+		},
+	}
+	expected := `steps:
+  - label: test things
+    key: test
+    command: make test
+    agents:
+      queue: test
+    plugins:
+      - docker#v3.7.0:
+          image: golang
+      - cache#v1.0.0
+    timeout_in_minutes: 10
+  - wait: ~
+    continue_on_failure: true
+  - block: Release
+    fields:
+      - key: stream
+        options:
+          - label: Beta
+            value: beta
+        select: Stream
+      - key: notes
+        required: false
+        text: Release notes
+  - group: Deploy
+    steps:
+      - trigger: deploy
+        async: true
+        build:
+          branch: master
+    depends_on:
+      - test
+    if: build.branch == "master"
+`
+
+	d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, raw)
+	steps, err := renderStepBlocks(d.Get("step").([]interface{}))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, steps)
+	errs, err := pipelineschema.Validate(steps, false)
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// Reading the rendered steps back should give the same blocks.
+	flattened, err := flattenStepBlocks(steps)
+	assert.NoError(t, err)
+	read := schema.TestResourceDataRaw(t, resourcePipeline().Schema, map[string]interface{}{})
+	assert.NoError(t, read.Set("step", flattened))
+	assert.Equal(t, d.Get("step"), read.Get("step"))
+}
+
+func TestRenderStepBlocksInvalid(t *testing.T) {
+	testCases := []struct {
+		description string
+		step        map[string]interface{}
+	}{
+		{
+			description: "no step type",
+			step:        map[string]interface{}{},
+		},
+		{
+			description: "multiple step types",
+			step: map[string]interface{}{
+				"command_step": []interface{}{map[string]interface{}{"command": "make test"}},
+				"wait_step":    []interface{}{map[string]interface{}{}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := renderStepBlocks([]interface{}{tc.step})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFlattenStepBlocks(t *testing.T) {
+	steps, err := flattenStepBlocks(`
+- command:
+    - make lint
+    - make test
+  agents: ["queue=test"]
+  depends_on:
+    - step: setup
+      allow_failure: true
+- wait
+`)
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+
+	command := steps[0].(map[string]interface{})["command_step"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "make lint\nmake test", command["command"])
+	assert.Equal(t, map[string]interface{}{"queue": "test"}, command["agents"])
+	assert.Equal(t, []interface{}{"setup"}, command["depends_on"])
+	assert.Contains(t, steps[1], "wait_step")
+}
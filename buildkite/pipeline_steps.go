@@ -0,0 +1,70 @@
+package buildkite
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/pipelineschema"
+	"gopkg.in/yaml.v3"
+)
+
+// parseSteps decodes a pipeline configuration. JSON is a subset of YAML so both formats are
+// accepted.
+func parseSteps(steps string) (interface{}, error) {
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(steps), &parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// stepsEqual reports whether two pipeline configurations describe the same pipeline, ignoring key
+// order, formatting and comments. A bare list of steps is the same as a pipeline with only those
+// steps. Configurations that fail to parse are compared as text.
+func stepsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	parsedA, err := parseSteps(a)
+	if err != nil {
+		return false
+	}
+	parsedB, err := parseSteps(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalizeSteps(parsedA), normalizeSteps(parsedB))
+}
+
+func normalizeSteps(parsed interface{}) interface{} {
+	if steps, ok := parsed.([]interface{}); ok {
+		return map[string]interface{}{"steps": steps}
+	}
+	return parsed
+}
+
+func suppressEquivalentSteps(k, old, new string, d *schema.ResourceData) bool {
+	return stepsEqual(old, new)
+}
+
+// validateSteps checks the planned steps against the Buildkite pipeline schema so that mistakes are
+// caught before a build runs.
+func validateSteps(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("steps") {
+		return nil
+	}
+	errs, err := pipelineschema.Validate(d.Get("steps").(string), d.Get("allow_experimental_step_keys").(bool))
+	if err != nil {
+		return fmt.Errorf("parsing steps: %w", err)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	return fmt.Errorf("steps do not match the Buildkite pipeline schema:\n%s", strings.Join(msgs, "\n"))
+}
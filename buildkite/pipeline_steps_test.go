@@ -0,0 +1,81 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepsEqual(t *testing.T) {
+	const steps = `
+env:
+  IAM_ROLE: "some-role"
+steps:
+  - label: "test things"
+    command: "make test"
+`
+	testCases := []struct {
+		description string
+		other       string
+		expected    bool
+	}{
+		{
+			description: "identical",
+			other:       steps,
+			expected:    true,
+		},
+		{
+			description: "reordered keys and comments",
+			other: `# Runs the tests.
+steps:
+- command: make test # the only step
+  label: test things
+env: {IAM_ROLE: some-role}
+`,
+			expected: true,
+		},
+		{
+			description: "json",
+			other:       `{"steps": [{"label": "test things", "command": "make test"}], "env": {"IAM_ROLE": "some-role"}}`,
+			expected:    true,
+		},
+		{
+			description: "changed value",
+			other: `
+env:
+  IAM_ROLE: "other-role"
+steps:
+  - label: "test things"
+    command: "make test"
+`,
+			expected: false,
+		},
+		{
+			description: "reordered steps",
+			other: `
+env:
+  IAM_ROLE: "some-role"
+steps:
+  - command: "make test"
+  - label: "test things"
+`,
+			expected: false,
+		},
+		{
+			description: "invalid yaml",
+			other:       "steps: [",
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, stepsEqual(steps, tc.other))
+		})
+	}
+}
+
+func TestStepsEqualList(t *testing.T) {
+	assert.True(t, stepsEqual("- command: make test\n", "steps:\n  - command: make test\n"))
+	assert.False(t, stepsEqual("- command: make test\n", "env: {A: b}\nsteps:\n  - command: make test\n"))
+}
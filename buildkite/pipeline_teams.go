@@ -0,0 +1,125 @@
+package buildkite
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+	"github.com/shurcooL/graphql"
+)
+
+func pipelineTeamSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeSet,
+		Optional:         true,
+		Description:      "Teams given access to the pipeline when it is created, so it is never visible to the wrong teams. Don't also manage these teams with `buildkite_team_pipeline`.",
+		DiffSuppressFunc: suppressClonedDiff,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"team_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The GraphQL ID of the team.",
+				},
+				"access_level": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice([]string{"MANAGE_BUILD_AND_READ", "BUILD_AND_READ", "READ_ONLY"}, false),
+				},
+			},
+		},
+	}
+}
+
+// teamAccessLevels returns the access level of each team in a set of team blocks by team ID.
+func teamAccessLevels(teams interface{}) map[string]string {
+	result := map[string]string{}
+	for _, t := range teams.(*schema.Set).List() {
+		team := t.(map[string]interface{})
+		result[team["team_id"].(string)] = team["access_level"].(string)
+	}
+	return result
+}
+
+// teamUUIDsFromSchema looks up the REST UUIDs of the configured teams, which are needed to create a
+// pipeline with them.
+func teamUUIDsFromSchema(d *schema.ResourceData, bk *client.Client) ([]string, error) {
+	var uuids []string
+	for id := range teamAccessLevels(d.Get("team")) {
+		team, err := bk.ReadTeam(id)
+		if err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, string(team.UUID))
+	}
+	return uuids, nil
+}
+
+// syncPipelineTeams gives the configured teams their access levels and removes teams that were
+// dropped from the configuration. Teams not managed by this pipeline are left alone.
+func syncPipelineTeams(d *schema.ResourceData, bk *client.Client) error {
+	o, n := d.GetChange("team")
+	old, configured := teamAccessLevels(o), teamAccessLevels(n)
+	if len(old) == 0 && len(configured) == 0 {
+		return nil
+	}
+	teamPipelines, err := bk.ReadTeamPipelines(d.Id())
+	if err != nil {
+		return err
+	}
+	current := map[string]client.TeamPipeline{}
+	for _, tp := range teamPipelines {
+		current[string(tp.Team.ID)] = tp
+	}
+
+	for id, accessLevel := range configured {
+		tp, ok := current[id]
+		if !ok {
+			tp = client.TeamPipeline{AccessLevel: graphql.String(accessLevel)}
+			tp.Team.ID = graphql.String(id)
+			tp.Pipeline.ID = graphql.String(d.Id())
+			if err := bk.CreateTeamPipeline(&tp); err != nil {
+				return err
+			}
+			continue
+		}
+		if string(tp.AccessLevel) != accessLevel {
+			tp.AccessLevel = graphql.String(accessLevel)
+			if err := bk.UpdateTeamPipeline(&tp); err != nil {
+				return err
+			}
+		}
+	}
+	for id := range old {
+		if _, ok := configured[id]; ok {
+			continue
+		}
+		if tp, ok := current[id]; ok {
+			if err := bk.DeleteTeamPipeline(&tp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readPipelineTeams refreshes the access levels of the configured teams.
+func readPipelineTeams(d *schema.ResourceData, bk *client.Client) error {
+	configured := teamAccessLevels(d.Get("team"))
+	if len(configured) == 0 {
+		return nil
+	}
+	teamPipelines, err := bk.ReadTeamPipelines(d.Id())
+	if err != nil {
+		return err
+	}
+	teams := []interface{}{}
+	for _, tp := range teamPipelines {
+		if _, ok := configured[string(tp.Team.ID)]; ok {
+			teams = append(teams, map[string]interface{}{
+				"team_id":      string(tp.Team.ID),
+				"access_level": string(tp.AccessLevel),
+			})
+		}
+	}
+	return d.Set("team", teams)
+}
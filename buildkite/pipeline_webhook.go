@@ -0,0 +1,49 @@
+package buildkite
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+)
+
+// Values of webhook_status.
+const (
+	webhookCreated     = "created"
+	webhookFailed      = "failed"
+	webhookUnsupported = "unsupported"
+)
+
+// diffPipelineWebhook plans creating the webhook again when the repository or auto_create_webhook
+// changes. Failed attempts aren't retried otherwise, as failures such as the repository not being
+// connected to the GitHub App would change webhook_status on every plan.
+func diffPipelineWebhook(d *schema.ResourceDiff, m interface{}) error {
+	if !d.Get("auto_create_webhook").(bool) || d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("repository") || d.HasChange("auto_create_webhook") {
+		return d.SetNewComputed("webhook_status")
+	}
+	return nil
+}
+
+// createPipelineWebhook creates the webhook for the pipeline's repository if auto_create_webhook is
+// set, when the pipeline is created or either of them changes. Failures are recorded in webhook_status and logged rather
+// than failing the apply, as the pipeline itself was saved and can build without it.
+func createPipelineWebhook(d *schema.ResourceData, bk *client.Client) error {
+	if !d.Get("auto_create_webhook").(bool) {
+		return nil
+	}
+	if !d.HasChange("repository") && !d.HasChange("auto_create_webhook") {
+		return nil
+	}
+	if d.Get("repository_provider").(string) != "github" {
+		log.Printf("[WARN] Buildkite can only create webhooks for GitHub repositories, not %s", d.Get("repository").(string))
+		return d.Set("webhook_status", webhookUnsupported)
+	}
+	if err := bk.CreatePipelineWebhook(d.Get("slug").(string)); err != nil {
+		log.Printf("[WARN] Creating the webhook for pipeline %s: %s", d.Get("slug").(string), err)
+		return d.Set("webhook_status", webhookFailed)
+	}
+	return d.Set("webhook_status", webhookCreated)
+}
@@ -0,0 +1,88 @@
+package buildkite
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineWebhookDiff(t *testing.T) {
+	testCases := []struct {
+		description string
+		status      string
+		wasEnabled  bool
+		raw         map[string]interface{}
+		retried     bool
+	}{
+		{
+			description: "created",
+			status:      webhookCreated,
+			wasEnabled:  true,
+			raw:         map[string]interface{}{"auto_create_webhook": true},
+		},
+		{
+			description: "failed",
+			status:      webhookFailed,
+			wasEnabled:  true,
+			raw:         map[string]interface{}{"auto_create_webhook": true},
+		},
+		{
+			description: "repository changed",
+			status:      webhookFailed,
+			wasEnabled:  true,
+			raw: map[string]interface{}{
+				"auto_create_webhook": true,
+				"repository":          "git@github.com:buildkite/agent.git",
+			},
+			retried: true,
+		},
+		{
+			description: "enabled again",
+			status:      webhookFailed,
+			raw:         map[string]interface{}{"auto_create_webhook": true},
+			retried:     true,
+		},
+		{
+			description: "disabled",
+			status:      webhookFailed,
+			wasEnabled:  true,
+			raw:         map[string]interface{}{"auto_create_webhook": false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tc.raw["name"] = "test"
+			if _, ok := tc.raw["repository"]; !ok {
+				tc.raw["repository"] = "git@github.com:buildkite/terraform-provider-buildkite.git"
+			}
+			tc.raw["steps"] = "steps: []\n"
+			r := resourcePipeline()
+			state := &terraform.InstanceState{ID: "UGlwZWxpbmUtLS0x", Attributes: map[string]string{
+				"name":                         "test",
+				"repository":                   "git@github.com:buildkite/terraform-provider-buildkite.git",
+				"steps":                        "steps: []\n",
+				"archived":                     "false",
+				"archive_on_destroy":           "false",
+				"deletion_protection":          "false",
+				"cancel_builds_on_destroy":     "false",
+				"allow_experimental_step_keys": "false",
+				"auto_create_webhook":          strconv.FormatBool(tc.wasEnabled),
+				"steps_interpolation":          "buildkite",
+				"webhook_status":               tc.status,
+			}}
+			diff, err := schema.InternalMap(r.Schema).Diff(state, terraform.NewResourceConfigRaw(tc.raw), r.CustomizeDiff, nil, true)
+			assert.NoError(t, err)
+			retried := false
+			if diff != nil {
+				if attr, ok := diff.Attributes["webhook_status"]; ok {
+					retried = attr.NewComputed
+				}
+			}
+			assert.Equal(t, tc.retried, retried)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package pipelineschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/conditional"
+	"gopkg.in/yaml.v3"
+)
+
+// validateConditionals parses the `if` expressions of the steps and notifications in a pipeline.
+// Error positions are translated to the line and column of the configuration where possible.
+func validateConditionals(config string, root *yaml.Node) []*Error {
+	v := &conditionalValidator{lines: strings.Split(config, "\n")}
+	switch root.Kind {
+	case yaml.SequenceNode:
+		v.steps(root, []string{"steps"})
+	case yaml.MappingNode:
+		if steps := mappingValue(root, "steps"); steps != nil {
+			v.steps(steps, []string{"steps"})
+		}
+		v.notify(root, nil)
+	}
+	return v.errs
+}
+
+type conditionalValidator struct {
+	lines []string
+	errs  []*Error
+}
+
+func (v *conditionalValidator) steps(node *yaml.Node, path []string) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, step := range node.Content {
+		step = resolveAlias(step)
+		if step.Kind != yaml.MappingNode {
+			continue
+		}
+		stepPath := append(append([]string{}, path...), strconv.Itoa(i))
+		v.check(mappingValue(step, "if"), append(stepPath, "if"))
+		v.notify(step, stepPath)
+		if steps := mappingValue(step, "steps"); steps != nil {
+			v.steps(steps, append(stepPath, "steps"))
+		}
+	}
+}
+
+func (v *conditionalValidator) notify(node *yaml.Node, path []string) {
+	notify := mappingValue(node, "notify")
+	if notify == nil || notify.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, notification := range notify.Content {
+		notification = resolveAlias(notification)
+		if notification.Kind != yaml.MappingNode {
+			continue
+		}
+		notificationPath := append(append([]string{}, path...), "notify", strconv.Itoa(i), "if")
+		v.check(mappingValue(notification, "if"), notificationPath)
+	}
+}
+
+func (v *conditionalValidator) check(node *yaml.Node, path []string) {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return
+	}
+	_, err := conditional.Parse(node.Value)
+	e, ok := err.(*conditional.Error)
+	if !ok {
+		return
+	}
+	line, column := v.position(node, e.Position)
+	v.errs = append(v.errs, &Error{
+		Path:    formatPath(path),
+		Line:    line,
+		Message: fmt.Sprintf("invalid conditional at column %d: %s", column, e.Message),
+	})
+}
+
+// position translates a position within an expression to a position in the configuration. Quoted
+// and plain scalars start where the node does. Block scalars start on the line after the indicator
+// and are indented like the first line of their content.
+func (v *conditionalValidator) position(node *yaml.Node, pos conditional.Position) (int, int) {
+	switch node.Style {
+	case yaml.LiteralStyle, yaml.FoldedStyle:
+		line := node.Line + pos.Line
+		indent := 0
+		if line <= len(v.lines) {
+			text := v.lines[line-1]
+			indent = len(text) - len(strings.TrimLeft(text, " "))
+		}
+		return line, indent + pos.Column
+	}
+	if pos.Line > 1 {
+		return node.Line + pos.Line - 1, pos.Column
+	}
+	column := node.Column + pos.Column - 1
+	if node.Style == yaml.DoubleQuotedStyle || node.Style == yaml.SingleQuotedStyle {
+		column++
+	}
+	return node.Line, column
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		return node.Alias
+	}
+	return node
+}
+
+// mappingValue returns the value for the given key of a mapping.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil
+}
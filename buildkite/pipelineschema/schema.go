@@ -0,0 +1,526 @@
+package pipelineschema
+
+// schemaJSON is the Buildkite pipeline schema, transcribed from
+// https://github.com/buildkite/pipeline-schema. Keep it in sync when Buildkite adds new step keys;
+// until then users can set allowUnknownKeys to use them.
+const schemaJSON = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "JSON schema for Buildkite pipeline configuration files",
+  "anyOf": [
+    {
+      "type": "object",
+      "properties": {
+        "env": { "$ref": "#/definitions/env" },
+        "agents": { "$ref": "#/definitions/agents" },
+        "notify": { "$ref": "#/definitions/buildNotify" },
+        "steps": { "$ref": "#/definitions/pipelineSteps" }
+      },
+      "required": ["steps"]
+    },
+    { "$ref": "#/definitions/pipelineSteps" }
+  ],
+  "definitions": {
+    "pipelineSteps": {
+      "type": "array",
+      "items": {
+        "anyOf": [
+          { "$ref": "#/definitions/stringWaitStep" },
+          { "$ref": "#/definitions/stringBlockStep" },
+          { "$ref": "#/definitions/stringInputStep" },
+          { "$ref": "#/definitions/commandStep" },
+          { "$ref": "#/definitions/waitStep" },
+          { "$ref": "#/definitions/blockStep" },
+          { "$ref": "#/definitions/inputStep" },
+          { "$ref": "#/definitions/triggerStep" },
+          { "$ref": "#/definitions/groupStep" }
+        ]
+      }
+    },
+    "groupSteps": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "anyOf": [
+          { "$ref": "#/definitions/stringWaitStep" },
+          { "$ref": "#/definitions/stringBlockStep" },
+          { "$ref": "#/definitions/stringInputStep" },
+          { "$ref": "#/definitions/commandStep" },
+          { "$ref": "#/definitions/waitStep" },
+          { "$ref": "#/definitions/blockStep" },
+          { "$ref": "#/definitions/inputStep" },
+          { "$ref": "#/definitions/triggerStep" }
+        ]
+      }
+    },
+    "env": {
+      "type": "object"
+    },
+    "agents": {
+      "anyOf": [
+        { "type": "object" },
+        { "type": "array", "items": { "type": "string" } }
+      ]
+    },
+    "allowDependencyFailure": {
+      "type": "boolean"
+    },
+    "branches": {
+      "anyOf": [
+        { "type": "string" },
+        { "type": "array", "items": { "type": "string" } }
+      ]
+    },
+    "cancelOnBuildFailing": {
+      "type": "boolean"
+    },
+    "dependsOn": {
+      "anyOf": [
+        { "type": "null" },
+        { "type": "string" },
+        {
+          "type": "array",
+          "items": {
+            "anyOf": [
+              { "type": "string" },
+              {
+                "type": "object",
+                "properties": {
+                  "step": { "type": "string" },
+                  "allow_failure": { "type": "boolean" }
+                },
+                "required": ["step"],
+                "additionalProperties": false
+              }
+            ]
+          }
+        }
+      ]
+    },
+    "identifier": {
+      "type": "string"
+    },
+    "if": {
+      "type": "string"
+    },
+    "key": {
+      "type": "string"
+    },
+    "label": {
+      "type": "string"
+    },
+    "prompt": {
+      "type": "string"
+    },
+    "skip": {
+      "anyOf": [
+        { "type": "boolean" },
+        { "type": "string" }
+      ]
+    },
+    "softFail": {
+      "anyOf": [
+        { "type": "boolean" },
+        {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "exit_status": {
+                "anyOf": [
+                  { "type": "string", "enum": ["*"] },
+                  { "type": "integer" }
+                ]
+              }
+            },
+            "additionalProperties": false
+          }
+        }
+      ]
+    },
+    "fields": {
+      "type": "array",
+      "items": {
+        "anyOf": [
+          { "$ref": "#/definitions/textField" },
+          { "$ref": "#/definitions/selectField" }
+        ]
+      }
+    },
+    "textField": {
+      "type": "object",
+      "properties": {
+        "text": { "type": "string" },
+        "key": { "type": "string" },
+        "hint": { "type": "string" },
+        "required": { "type": "boolean" },
+        "default": { "type": "string" },
+        "format": { "type": "string" }
+      },
+      "required": ["key"],
+      "additionalProperties": false
+    },
+    "selectField": {
+      "type": "object",
+      "properties": {
+        "select": { "type": "string" },
+        "key": { "type": "string" },
+        "hint": { "type": "string" },
+        "required": { "type": "boolean" },
+        "default": {
+          "anyOf": [
+            { "type": "string" },
+            { "type": "array", "items": { "type": "string" } }
+          ]
+        },
+        "multiple": { "type": "boolean" },
+        "options": {
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "object",
+            "properties": {
+              "label": { "type": "string" },
+              "value": { "type": "string" },
+              "hint": { "type": "string" },
+              "required": { "type": "boolean" }
+            },
+            "required": ["label", "value"],
+            "additionalProperties": false
+          }
+        }
+      },
+      "required": ["key", "options"],
+      "additionalProperties": false
+    },
+    "buildNotify": {
+      "type": "array",
+      "items": {
+        "anyOf": [
+          { "type": "string", "enum": ["github_check", "github_commit_status"] },
+          {
+            "type": "object",
+            "properties": {
+              "email": { "type": "string" },
+              "basecamp_campfire": { "type": "string" },
+              "slack": {
+                "anyOf": [
+                  { "type": "string" },
+                  {
+                    "type": "object",
+                    "properties": {
+                      "channels": { "type": "array", "items": { "type": "string" } },
+                      "message": { "type": "string" }
+                    },
+                    "additionalProperties": false
+                  }
+                ]
+              },
+              "webhook": { "type": "string" },
+              "pagerduty_change_event": { "type": "string" },
+              "github_commit_status": {
+                "type": "object",
+                "properties": {
+                  "context": { "type": "string" }
+                },
+                "additionalProperties": false
+              },
+              "github_check": {
+                "type": "object",
+                "properties": {
+                  "context": { "type": "string" }
+                },
+                "additionalProperties": false
+              },
+              "if": { "$ref": "#/definitions/if" }
+            },
+            "additionalProperties": false
+          }
+        ]
+      }
+    },
+    "stepNotify": {
+      "type": "array",
+      "items": {
+        "anyOf": [
+          { "type": "string", "enum": ["github_check", "github_commit_status"] },
+          {
+            "type": "object",
+            "properties": {
+              "basecamp_campfire": { "type": "string" },
+              "slack": {
+                "anyOf": [
+                  { "type": "string" },
+                  {
+                    "type": "object",
+                    "properties": {
+                      "channels": { "type": "array", "items": { "type": "string" } },
+                      "message": { "type": "string" }
+                    },
+                    "additionalProperties": false
+                  }
+                ]
+              },
+              "github_commit_status": {
+                "type": "object",
+                "properties": {
+                  "context": { "type": "string" }
+                },
+                "additionalProperties": false
+              },
+              "github_check": {
+                "type": "object",
+                "properties": {
+                  "context": { "type": "string" }
+                },
+                "additionalProperties": false
+              },
+              "if": { "$ref": "#/definitions/if" }
+            },
+            "additionalProperties": false
+          }
+        ]
+      }
+    },
+    "plugins": {
+      "anyOf": [
+        {
+          "type": "array",
+          "items": {
+            "anyOf": [
+              { "type": "string" },
+              { "type": "object", "maxProperties": 1 }
+            ]
+          }
+        },
+        { "type": "object" }
+      ]
+    },
+    "automaticRetry": {
+      "type": "object",
+      "properties": {
+        "exit_status": {
+          "anyOf": [
+            { "type": "string", "enum": ["*"] },
+            { "type": "integer" },
+            { "type": "array", "items": { "type": "integer" } }
+          ]
+        },
+        "limit": { "type": "integer", "minimum": 0, "maximum": 10 },
+        "signal": { "type": "string" },
+        "signal_reason": {
+          "type": "string",
+          "enum": ["*", "none", "agent_refused", "agent_stop", "cancel", "process_run_error", "signature_rejected"]
+        }
+      },
+      "additionalProperties": false
+    },
+    "commandStep": {
+      "type": "object",
+      "properties": {
+        "agents": { "$ref": "#/definitions/agents" },
+        "allow_dependency_failure": { "$ref": "#/definitions/allowDependencyFailure" },
+        "artifact_paths": {
+          "anyOf": [
+            { "type": "string" },
+            { "type": "array", "items": { "type": "string" } }
+          ]
+        },
+        "branches": { "$ref": "#/definitions/branches" },
+        "cache": {
+          "anyOf": [
+            { "type": "string" },
+            { "type": "array", "items": { "type": "string" } },
+            { "type": "object" }
+          ]
+        },
+        "cancel_on_build_failing": { "$ref": "#/definitions/cancelOnBuildFailing" },
+        "command": {
+          "anyOf": [
+            { "type": "string" },
+            { "type": "array", "items": { "type": "string" } }
+          ]
+        },
+        "commands": {
+          "anyOf": [
+            { "type": "string" },
+            { "type": "array", "items": { "type": "string" } }
+          ]
+        },
+        "concurrency": { "type": "integer" },
+        "concurrency_group": { "type": "string" },
+        "concurrency_method": { "type": "string", "enum": ["ordered", "eager"] },
+        "depends_on": { "$ref": "#/definitions/dependsOn" },
+        "env": { "$ref": "#/definitions/env" },
+        "id": { "$ref": "#/definitions/identifier" },
+        "identifier": { "$ref": "#/definitions/identifier" },
+        "if": { "$ref": "#/definitions/if" },
+        "image": { "type": "string" },
+        "key": { "$ref": "#/definitions/key" },
+        "label": { "$ref": "#/definitions/label" },
+        "matrix": {
+          "anyOf": [
+            { "type": "array" },
+            { "type": "object" }
+          ]
+        },
+        "name": { "$ref": "#/definitions/label" },
+        "notify": { "$ref": "#/definitions/stepNotify" },
+        "parallelism": { "type": "integer" },
+        "plugins": { "$ref": "#/definitions/plugins" },
+        "priority": { "type": "integer" },
+        "retry": {
+          "type": "object",
+          "properties": {
+            "automatic": {
+              "anyOf": [
+                { "type": "boolean" },
+                { "$ref": "#/definitions/automaticRetry" },
+                { "type": "array", "items": { "$ref": "#/definitions/automaticRetry" } }
+              ]
+            },
+            "manual": {
+              "anyOf": [
+                { "type": "boolean" },
+                {
+                  "type": "object",
+                  "properties": {
+                    "allowed": { "type": "boolean" },
+                    "permit_on_passed": { "type": "boolean" },
+                    "reason": { "type": "string" }
+                  },
+                  "additionalProperties": false
+                }
+              ]
+            }
+          },
+          "additionalProperties": false
+        },
+        "signature": { "type": "object" },
+        "skip": { "$ref": "#/definitions/skip" },
+        "soft_fail": { "$ref": "#/definitions/softFail" },
+        "timeout_in_minutes": { "type": "integer", "minimum": 1 },
+        "type": { "type": "string", "enum": ["script", "command", "commands"] }
+      },
+      "additionalProperties": false
+    },
+    "stringWaitStep": {
+      "type": "string",
+      "enum": ["wait", "waiter"]
+    },
+    "waitStep": {
+      "type": "object",
+      "properties": {
+        "allow_dependency_failure": { "$ref": "#/definitions/allowDependencyFailure" },
+        "branches": { "$ref": "#/definitions/branches" },
+        "continue_on_failure": { "type": "boolean" },
+        "depends_on": { "$ref": "#/definitions/dependsOn" },
+        "id": { "$ref": "#/definitions/identifier" },
+        "identifier": { "$ref": "#/definitions/identifier" },
+        "if": { "$ref": "#/definitions/if" },
+        "key": { "$ref": "#/definitions/key" },
+        "type": { "type": "string", "enum": ["wait", "waiter"] },
+        "wait": { "type": ["string", "null"] },
+        "waiter": { "type": ["string", "null"] }
+      },
+      "additionalProperties": false
+    },
+    "stringBlockStep": {
+      "type": "string",
+      "enum": ["block", "manual"]
+    },
+    "blockStep": {
+      "type": "object",
+      "properties": {
+        "allow_dependency_failure": { "$ref": "#/definitions/allowDependencyFailure" },
+        "block": { "type": "string" },
+        "blocked_state": { "type": "string", "enum": ["passed", "failed", "running"] },
+        "branches": { "$ref": "#/definitions/branches" },
+        "depends_on": { "$ref": "#/definitions/dependsOn" },
+        "fields": { "$ref": "#/definitions/fields" },
+        "id": { "$ref": "#/definitions/identifier" },
+        "identifier": { "$ref": "#/definitions/identifier" },
+        "if": { "$ref": "#/definitions/if" },
+        "key": { "$ref": "#/definitions/key" },
+        "label": { "$ref": "#/definitions/label" },
+        "name": { "$ref": "#/definitions/label" },
+        "prompt": { "$ref": "#/definitions/prompt" },
+        "type": { "type": "string", "enum": ["block", "manual"] }
+      },
+      "additionalProperties": false
+    },
+    "stringInputStep": {
+      "type": "string",
+      "enum": ["input"]
+    },
+    "inputStep": {
+      "type": "object",
+      "properties": {
+        "allow_dependency_failure": { "$ref": "#/definitions/allowDependencyFailure" },
+        "branches": { "$ref": "#/definitions/branches" },
+        "depends_on": { "$ref": "#/definitions/dependsOn" },
+        "fields": { "$ref": "#/definitions/fields" },
+        "id": { "$ref": "#/definitions/identifier" },
+        "identifier": { "$ref": "#/definitions/identifier" },
+        "if": { "$ref": "#/definitions/if" },
+        "input": { "type": "string" },
+        "key": { "$ref": "#/definitions/key" },
+        "label": { "$ref": "#/definitions/label" },
+        "name": { "$ref": "#/definitions/label" },
+        "prompt": { "$ref": "#/definitions/prompt" },
+        "type": { "type": "string", "enum": ["input"] }
+      },
+      "additionalProperties": false
+    },
+    "triggerStep": {
+      "type": "object",
+      "properties": {
+        "allow_dependency_failure": { "$ref": "#/definitions/allowDependencyFailure" },
+        "async": { "type": "boolean" },
+        "branches": { "$ref": "#/definitions/branches" },
+        "build": {
+          "type": "object",
+          "properties": {
+            "branch": { "type": "string" },
+            "commit": { "type": "string" },
+            "env": { "$ref": "#/definitions/env" },
+            "message": { "type": "string" },
+            "meta_data": { "type": "object" }
+          },
+          "additionalProperties": false
+        },
+        "depends_on": { "$ref": "#/definitions/dependsOn" },
+        "id": { "$ref": "#/definitions/identifier" },
+        "identifier": { "$ref": "#/definitions/identifier" },
+        "if": { "$ref": "#/definitions/if" },
+        "key": { "$ref": "#/definitions/key" },
+        "label": { "$ref": "#/definitions/label" },
+        "name": { "$ref": "#/definitions/label" },
+        "skip": { "$ref": "#/definitions/skip" },
+        "soft_fail": { "$ref": "#/definitions/softFail" },
+        "trigger": { "type": "string" },
+        "type": { "type": "string", "enum": ["trigger"] }
+      },
+      "required": ["trigger"],
+      "additionalProperties": false
+    },
+    "groupStep": {
+      "type": "object",
+      "properties": {
+        "allow_dependency_failure": { "$ref": "#/definitions/allowDependencyFailure" },
+        "depends_on": { "$ref": "#/definitions/dependsOn" },
+        "group": { "type": ["string", "null"] },
+        "id": { "$ref": "#/definitions/identifier" },
+        "identifier": { "$ref": "#/definitions/identifier" },
+        "if": { "$ref": "#/definitions/if" },
+        "key": { "$ref": "#/definitions/key" },
+        "label": { "$ref": "#/definitions/label" },
+        "name": { "$ref": "#/definitions/label" },
+        "notify": { "$ref": "#/definitions/buildNotify" },
+        "skip": { "$ref": "#/definitions/skip" },
+        "steps": { "$ref": "#/definitions/groupSteps" }
+      },
+      "required": ["group", "steps"],
+      "additionalProperties": false
+    }
+  }
+}`
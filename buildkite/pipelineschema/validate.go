@@ -0,0 +1,179 @@
+// Package pipelineschema validates pipeline configurations against the Buildkite pipeline schema.
+package pipelineschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// contextDelimiter separates the segments of a gojsonschema context. Keys in a pipeline can contain
+// dots so the default delimiter can't be used.
+const contextDelimiter = "\x00"
+
+var schema *gojsonschema.Schema
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schemaJSON))
+	if err != nil {
+		panic(fmt.Sprintf("invalid pipeline schema: %s", err))
+	}
+	schema = s
+}
+
+// Error is a single violation of the pipeline schema.
+type Error struct {
+	// Path is the location of the violation within the pipeline, e.g. steps[0].agents.
+	Path string
+	// Line is the line of the configuration the violation is on, starting from 1.
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Message)
+}
+
+// Validate checks a pipeline configuration, written in YAML or JSON, against the Buildkite
+// pipeline schema. Keys unknown to the schema are rejected unless allowUnknownKeys is set, which
+// allows experimental keys that have not made it into the schema yet. The `if` expressions of
+// steps and notifications are checked as well.
+//
+// An error is returned if the configuration can't be parsed at all.
+func Validate(config string, allowUnknownKeys bool) ([]*Error, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(config), &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		// An empty document has nothing to validate.
+		return nil, nil
+	}
+	var document interface{}
+	if err := root.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []*Error
+	for _, e := range result.Errors() {
+		if allowUnknownKeys && e.Type() == "additional_property_not_allowed" {
+			continue
+		}
+		segments := strings.Split(e.Context().String(contextDelimiter), contextDelimiter)[1:]
+		if isAlternative(e) && hasMoreSpecificError(e, segments, result.Errors()) {
+			continue
+		}
+		node := lookup(root.Content[0], segments)
+		path := formatPath(segments)
+		if e.Type() == "additional_property_not_allowed" {
+			property, _ := e.Details()["property"].(string)
+			if key := lookupKey(node, property); key != nil {
+				node = key
+			}
+			path = formatPath(append(segments, property))
+		}
+		errs = append(errs, &Error{
+			Path:    path,
+			Line:    node.Line,
+			Message: e.Description(),
+		})
+	}
+	return append(errs, validateConditionals(config, root.Content[0])...), nil
+}
+
+// isAlternative reports whether the error is for a value that matched none of several
+// alternatives, such as the different kinds of step.
+func isAlternative(e gojsonschema.ResultError) bool {
+	switch e.Type() {
+	case "number_any_of", "number_one_of":
+		return true
+	}
+	return false
+}
+
+// hasMoreSpecificError reports whether another error is at or below the given location. Those
+// errors come from the closest matching alternative and explain the failure better, or were
+// allowed, in which case the alternative would have matched without them.
+func hasMoreSpecificError(e gojsonschema.ResultError, segments []string, errs []gojsonschema.ResultError) bool {
+	prefix := strings.Join(segments, contextDelimiter)
+	for _, other := range errs {
+		if other == e || isAlternative(other) {
+			continue
+		}
+		otherSegments := strings.Split(other.Context().String(contextDelimiter), contextDelimiter)[1:]
+		otherPath := strings.Join(otherSegments, contextDelimiter)
+		if otherPath == prefix || strings.HasPrefix(otherPath, prefix+contextDelimiter) || prefix == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the deepest node along the given path.
+func lookup(node *yaml.Node, segments []string) *yaml.Node {
+	for _, segment := range segments {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(segment); err == nil && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+	return node
+}
+
+// lookupKey returns the key node for the given key of a mapping.
+func lookupKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// formatPath renders path segments the way they would be written in a pipeline, e.g.
+// steps[0].plugins.
+func formatPath(segments []string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			fmt.Fprintf(&b, "[%s]", segment)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(segment)
+	}
+	if b.Len() == 0 {
+		return "(root)"
+	}
+	return b.String()
+}
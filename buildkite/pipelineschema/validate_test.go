@@ -0,0 +1,181 @@
+package pipelineschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description      string
+		config           string
+		allowUnknownKeys bool
+		expected         []string
+	}{
+		{
+			description: "valid pipeline",
+			config: `
+env:
+  IAM_ROLE: "some-role"
+agents:
+  queue: default
+steps:
+  - label: "test things"
+    command: "make test"
+    key: test
+    agents:
+      queue: test
+  - wait
+  - wait: ~
+    continue_on_failure: true
+  - block: "Release"
+    fields:
+      - select: "Stream"
+        key: stream
+        options:
+          - label: Beta
+            value: beta
+  - trigger: deploy
+    depends_on:
+      - step: test
+        allow_failure: true
+    build:
+      branch: main
+  - group: "Lint"
+    steps:
+      - command: "make lint"
+`,
+		},
+		{
+			description: "json",
+			config:      `{"steps": [{"command": "make test", "timeout_in_minutes": 10}]}`,
+		},
+		{
+			description: "list of steps",
+			config: `
+- command: "make test"
+- wait
+`,
+		},
+		{
+			description: "empty",
+			config:      "",
+		},
+		{
+			description: "misspelled key",
+			config: `
+steps:
+  - label: "test things"
+    command: "make test"
+    agnets:
+      queue: test
+`,
+			expected: []string{"line 5: steps[0].agnets: Additional property agnets is not allowed"},
+		},
+		{
+			description:      "misspelled key allowed",
+			allowUnknownKeys: true,
+			config: `
+steps:
+  - label: "test things"
+    command: "make test"
+    agnets:
+      queue: test
+`,
+		},
+		{
+			description: "wait with bad field",
+			config: `
+steps:
+  - command: "make test"
+  - wait: ~
+    continue_on_failure: "yes"
+`,
+			expected: []string{"line 5: steps[1].continue_on_failure: Invalid type. Expected: boolean, given: string"},
+		},
+		{
+			description: "invalid depends_on",
+			config: `
+steps:
+  - command: "make test"
+    depends_on:
+      - allow_failure: true
+`,
+			expected: []string{"line 5: steps[0].depends_on[0]: step is required"},
+		},
+		{
+			description: "missing steps",
+			config: `
+env:
+  FOO: bar
+`,
+			expected: []string{"line 2: (root): steps is required"},
+		},
+		{
+			description: "valid conditionals",
+			config: `
+notify:
+  - slack: "#builds"
+    if: build.state == "failed"
+steps:
+  - command: "make deploy"
+    if: build.branch == "main" && build.message !~ /skip deploy/i
+  - group: "Lint"
+    steps:
+      - command: "make lint"
+        if: |
+          build.pull_request.id != null
+          || build.env("LINT") == "1"
+`,
+		},
+		{
+			description: "invalid conditionals",
+			config: `
+notify:
+  - slack: "#builds"
+    if: build.state = "failed"
+steps:
+  - command: "make deploy"
+    if: "build.branch == main' && !build.tag"
+  - group: "Lint"
+    steps:
+      - command: "make lint"
+        if: |
+          build.pull_request.id != null
+          || build.env("LINT") == 1)
+`,
+			expected: []string{
+				`line 7: steps[0].if: invalid conditional at column 30: unterminated string`,
+				`line 13: steps[1].steps[0].if: invalid conditional at column 36: unexpected ")", expected an operator`,
+				`line 4: notify[0].if: invalid conditional at column 21: unexpected '=', use == to compare values`,
+			},
+		},
+		{
+			description: "invalid conditional in a list of steps",
+			config: `
+- command: "make test"
+  if: build.branch ==
+`,
+			expected: []string{"line 3: steps[0].if: invalid conditional at column 22: unexpected end of expression, expected a value"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			errs, err := Validate(tc.config, tc.allowUnknownKeys)
+			assert.NoError(t, err)
+
+			var actual []string
+			for _, e := range errs {
+				actual = append(actual, e.Error())
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestValidateInvalidYAML(t *testing.T) {
+	_, err := Validate("steps: [", false)
+	assert.Error(t, err)
+}
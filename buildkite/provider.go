@@ -2,6 +2,7 @@ package buildkite
 
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
 )
@@ -26,21 +27,49 @@ func Provider() terraform.ResourceProvider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc(TokenEnvVar, nil),
 			},
+			"secret_scanning": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      secretScanningWarn,
+				Description:  "What to do when a pipeline's `steps` or `env`, or a schedule's `env`, look like they contain secrets: `off`, `warn` or `error` to fail the plan. Terraform can't show warnings from this provider, so `warn` only writes to the provider log, which is hidden unless `TF_LOG` is set. Defaults to `warn`.",
+				ValidateFunc: validation.StringInSlice([]string{secretScanningOff, secretScanningWarn, secretScanningError}, false),
+			},
+			"secret_scanning_allowlist": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Regular expressions matching values that look like secrets but aren't.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
+			"buildkite_build":             resourceBuild(),
 			"buildkite_pipeline":          resourcePipeline(),
 			"buildkite_pipeline_schedule": resourcePipelineSchedule(),
+			"buildkite_pipeline_template": resourcePipelineTemplate(),
 			"buildkite_team":              resourceTeam(),
 			"buildkite_team_pipeline":     resourceTeamPipeline(),
 			"buildkite_team_member":       resourceTeamMember(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"buildkite_user": dataSourceUser(),
+			"buildkite_branch_filter_match": dataSourceBranchFilterMatch(),
+			"buildkite_build":               dataSourceBuild(),
+			"buildkite_pipeline":            dataSourcePipeline(),
+			"buildkite_pipelines":           dataSourcePipelines(),
+			"buildkite_user":                dataSourceUser(),
 		},
 		ConfigureFunc: createClient,
 	}
 }
 
+// providerConfig is passed to resources and data sources as their meta.
+type providerConfig struct {
+	client         *client.Client
+	secretScanning *secretScanning
+}
+
 func createClient(d *schema.ResourceData) (interface{}, error) {
 	org := d.Get("organization_slug").(string)
 	token := d.Get("api_token").(string)
@@ -49,5 +78,9 @@ func createClient(d *schema.ResourceData) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return cli, nil
+	scanning, err := secretScanningFromSchema(d)
+	if err != nil {
+		return nil, err
+	}
+	return &providerConfig{client: cli, secretScanning: scanning}, nil
 }
@@ -0,0 +1,205 @@
+package buildkite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+)
+
+func resourceBuild() *schema.Resource {
+	return &schema.Resource{
+		Description: "A resource that creates a build of a pipeline, e.g. to run a seed pipeline once after creating it. Destroying it forgets the build without canceling it.",
+		Schema: map[string]*schema.Schema{
+			"pipeline_slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"commit": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "HEAD",
+				Description: "The commit to build.",
+			},
+			"branch": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"message": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"env": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Environment variables for the build.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"meta_data": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Meta-data for the build, readable with `buildkite-agent meta-data get`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"wait_for_completion": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for the build to finish when creating it, up to the create timeout.",
+			},
+			"require_pass": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for the build to finish when creating it and fail unless it passed. A failed build is created again on the next apply.",
+			},
+			"number": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"web_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the build in the Buildkite UI.",
+			},
+			"state": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the build, e.g. `running`, `passed` or `failed`.",
+			},
+		},
+		Create: createBuild,
+		Read:   readBuild,
+		// Only the waiting behaviour can change, which only matters when creating the build.
+		Update: readBuild,
+		Delete: deleteBuild,
+		Importer: &schema.ResourceImporter{
+			State: importBuild,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+	}
+}
+
+func createBuild(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	slug := d.Get("pipeline_slug").(string)
+	build, err := bk.CreateBuild(slug, &client.CreateBuild{
+		Commit:   d.Get("commit").(string),
+		Branch:   d.Get("branch").(string),
+		Message:  d.Get("message").(string),
+		Env:      stringMap(d.Get("env").(map[string]interface{})),
+		MetaData: stringMap(d.Get("meta_data").(map[string]interface{})),
+	})
+	if err != nil {
+		return err
+	}
+	if build.Number == nil {
+		return fmt.Errorf("nil number for build of pipeline: %s", slug)
+	}
+	d.SetId(buildID(slug, *build.Number))
+	d.Set("number", *build.Number)
+
+	requirePass := d.Get("require_pass").(bool)
+	if d.Get("wait_for_completion").(bool) || requirePass {
+		waited, err := waitForBuild(bk, slug, *build.Number, d.Timeout(schema.TimeoutCreate))
+		if waited != nil {
+			build = waited
+		}
+		if err != nil {
+			setBuild(d, build)
+			return err
+		}
+	}
+	setBuild(d, build)
+	if state := d.Get("state").(string); requirePass && state != "passed" {
+		return fmt.Errorf("build %d of pipeline %s finished as %s instead of passed: %s", *build.Number, slug, state, d.Get("web_url").(string))
+	}
+	return nil
+}
+
+func readBuild(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	build, err := bk.ReadBuild(d.Get("pipeline_slug").(string), d.Get("number").(int))
+	if err != nil {
+		return err
+	}
+	setBuild(d, build)
+	return nil
+}
+
+// deleteBuild only removes the build from the state, as builds can't be deleted.
+func deleteBuild(d *schema.ResourceData, m interface{}) error {
+	return nil
+}
+
+// importBuild imports a build by its ID, which is the pipeline's slug and the build's number
+// separated by a slash.
+func importBuild(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected an ID of the form <pipeline slug>/<build number>, got %s", d.Id())
+	}
+	number, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid build number %s: %w", parts[1], err)
+	}
+	d.Set("pipeline_slug", parts[0])
+	d.Set("number", number)
+	return []*schema.ResourceData{d}, nil
+}
+
+func buildID(pipelineSlug string, number int) string {
+	return fmt.Sprintf("%s/%d", pipelineSlug, number)
+}
+
+// waitForBuild polls the build until it finishes or the timeout passes.
+func waitForBuild(bk *client.Client, pipelineSlug string, number int, timeout time.Duration) (*client.Build, error) {
+	var build *client.Build
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		b, err := bk.ReadBuild(pipelineSlug, number)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		build = b
+		if b.State == nil || !isFinishedBuildState(*b.State) {
+			return resource.RetryableError(fmt.Errorf("build %d of pipeline %s hasn't finished yet", number, pipelineSlug))
+		}
+		return nil
+	})
+	return build, err
+}
+
+func isFinishedBuildState(state string) bool {
+	for _, s := range client.FinishedBuildStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func setBuild(d *schema.ResourceData, build *client.Build) {
+	d.Set("number", build.Number)
+	d.Set("web_url", build.WebURL)
+	d.Set("state", build.State)
+}
+
+func stringMap(m map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package buildkite
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func testAccBuildConfig(name string) string {
+	return fmt.Sprintf(`
+resource "buildkite_pipeline" "test" {
+	name = "%s"
+	repository = "%s"
+	steps = <<EOF
+steps:
+  - label: "seed"
+    command: "echo $$SEED"
+EOF
+}
+
+resource "buildkite_build" "test" {
+	pipeline_slug = buildkite_pipeline.test.slug
+	branch = "master"
+	message = "Seed"
+	env = {
+	  SEED = "1"
+	}
+	meta_data = {
+	  release = "seed"
+	}
+}
+`, name, repoName)
+}
+
+func TestAccBuild(t *testing.T) {
+	rName := acctest.RandString(5)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactory,
+		CheckDestroy:      testAccPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("buildkite_build.test", "number", "1"),
+					resource.TestCheckResourceAttrSet("buildkite_build.test", "state"),
+					resource.TestMatchResourceAttr("buildkite_build.test", "web_url", regexp.MustCompile(`^https://buildkite\.com/.+/builds/1$`)),
+				),
+			},
+			{
+				ResourceName:            "buildkite_build.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"commit", "branch", "message", "env", "meta_data", "wait_for_completion", "require_pass", "state"},
+			},
+		},
+	})
+}
@@ -1,11 +1,21 @@
 package buildkite
 
 import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	buildkiteRest "github.com/buildkite/go-buildkite/v2/buildkite"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/branchfilter"
 	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/conditional"
+	"github.com/shurcooL/graphql"
 )
 
 func resourcePipeline() *schema.Resource {
@@ -20,25 +30,188 @@ func resourcePipeline() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"uuid": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The UUID of the pipeline, as used by the REST API.",
+			},
+			"web_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the pipeline in the Buildkite UI.",
+			},
+			"badge_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the pipeline's build status badge.",
+			},
+			"webhook_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL the repository provider should send webhooks to in order to trigger builds.",
+			},
+			"repository_provider": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the repository's provider, e.g. `github`, `gitlab` or `bitbucket`.",
+			},
 			"repository": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"steps": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The pipeline configuration as YAML or JSON. Changes to formatting, key order or comments are not considered a diff.",
+				DiffSuppressFunc: suppressEquivalentSteps,
+				AtLeastOneOf:     []string{"steps", "step", "source_pipeline_slug"},
+			},
+			"step": stepBlocksSchema(false),
+			"source_pipeline_slug": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The slug of a pipeline whose configuration, provider settings, timeouts and teams are copied when creating the pipeline, like duplicating it in the Buildkite UI. Attributes that are set take precedence, while the copied values are kept for those that aren't until they are set. Changing it after creation has no effect, and removing it stops keeping the copied values.",
+			},
+			"cloned_attributes": &schema.Schema{
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The attributes whose values were copied from `source_pipeline_slug` and are kept while they aren't set.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"archived": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the pipeline is archived. Archived pipelines keep their builds but can't run new ones. Changing the pipeline in Buildkite, e.g. its steps, briefly unarchives it while it is updated.",
+			},
+			"visibility": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Either `public` or `private`. Public pipelines can be viewed by anyone.",
+				ValidateFunc: validation.StringInSlice([]string{"public", "private"}, false),
+			},
+			"emoji": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An emoji shown next to the pipeline's name, e.g. `:rocket:`.",
+			},
+			"color": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "A hex color for the pipeline's emoji background, e.g. `#FF0000`.",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^#[0-9a-fA-F]{6}$`), "must be a hex color such as #FF0000"),
+			},
+			"tags": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Labels used to group and filter pipelines.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"cluster_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The GraphQL ID of the cluster the pipeline runs in. Pipelines without one use the unclustered agents. The REST API used to create pipelines has no cluster, so a new pipeline is briefly unclustered until it is moved into its cluster right after it is created.",
+			},
+			"pipeline_template_id": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The GraphQL ID of a pipeline template. Builds run the template's step configuration instead of `steps`.",
+				DiffSuppressFunc: suppressClonedDiff,
+			},
+			"default_timeout_in_minutes": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Description:      "The timeout for command steps that don't set their own. Must not exceed `maximum_timeout_in_minutes`.",
+				ValidateFunc:     validation.IntAtLeast(1),
+				DiffSuppressFunc: suppressClonedDiff,
+			},
+			"maximum_timeout_in_minutes": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Description:      "The longest timeout a command step can set.",
+				ValidateFunc:     validation.IntAtLeast(1),
+				DiffSuppressFunc: suppressClonedDiff,
+			},
+			"env": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Environment variables for every step, merged into the top level `env` of the configuration.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"default_agents": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Agent tags for steps that don't set their own, merged into the top level `agents` of the configuration.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"notify": notifySchema(),
+			"team":   pipelineTeamSchema(),
+			"archive_on_destroy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Archive the pipeline instead of deleting it on destroy, keeping its builds.",
+			},
+			"deletion_protection": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to destroy the pipeline. It must be set to `false` and applied before the pipeline can be destroyed.",
+			},
+			"cancel_builds_on_destroy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Cancel unfinished builds, i.e. those that are scheduled, running, failing or canceling, when destroying the pipeline, waiting up to the delete timeout for them to stop. Otherwise the pipeline isn't deleted while it has such builds.",
+			},
+			"allow_experimental_step_keys": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow keys in `steps` that the bundled Buildkite pipeline schema does not know about yet.",
+			},
+			"auto_create_webhook": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Ask Buildkite to create the webhook that triggers builds on pushes to the repository. Only GitHub repositories connected through the Buildkite GitHub App are supported.",
+			},
+			"webhook_status": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Whether the webhook was created when `auto_create_webhook` is set: `created`, `failed` or `unsupported` for repositories that aren't on GitHub. Failed webhooks are only retried when `repository` or `auto_create_webhook` changes.",
+			},
+			"steps_interpolation": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      stepsInterpolationBuildkite,
+				Description:  "How `$` in `steps` is treated. With `buildkite` Buildkite interpolates environment variables when the pipeline is uploaded and `$$` must be written for a literal `$`. With `literal` every `$` is escaped, so commands see them as written, except in references to `interpolated_variables`.",
+				ValidateFunc: validation.StringInSlice([]string{stepsInterpolationBuildkite, stepsInterpolationLiteral}, false),
+			},
+			"interpolated_variables": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Environment variables that Buildkite still interpolates when `steps_interpolation` is `literal`, e.g. `BUILDKITE_BRANCH`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`), "must be an environment variable name"),
+				},
 			},
 			"branch_configuration": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBranchFilter,
 			},
 			"cancel_running_branch_builds": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
 			"cancel_running_branch_builds_filter": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBranchFilter,
 			},
 			"default_branch": &schema.Schema{
 				Type:     schema.TypeString,
@@ -53,13 +226,26 @@ func resourcePipeline() *schema.Resource {
 				Optional: true,
 			},
 			"skip_queued_branch_builds_filter": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBranchFilter,
 			},
 			"provider_settings": &schema.Schema{
-				Type:     schema.TypeMap,
-				Optional: true,
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Description:   "Untyped provider settings, sent as GitHub settings regardless of the repository's provider.",
+				ConflictsWith: providerSettingsBlocks,
+				ValidateFunc:  validateProviderSettings,
 			},
+			"github_settings": providerSettingsBlockSchema("github_settings",
+				"Settings for pipelines building from GitHub repositories. Conflicts with `provider_settings`.",
+				gitHubSettingsSchema()),
+			"github_enterprise_settings": providerSettingsBlockSchema("github_enterprise_settings",
+				"Settings for pipelines building from GitHub Enterprise repositories. Conflicts with `provider_settings`.",
+				pullRequestSettingsSchema()),
+			"bitbucket_settings": providerSettingsBlockSchema("bitbucket_settings",
+				"Settings for pipelines building from Bitbucket repositories. Conflicts with `provider_settings`.",
+				pullRequestSettingsSchema()),
 		},
 		Create: createPipeline,
 		Read:   readPipeline,
@@ -68,6 +254,21 @@ func resourcePipeline() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: importPipeline,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			diffStepBlocks,
+			validateSteps,
+			validatePipelineDefaults,
+			validateTimeouts,
+			scanPipelineSecrets,
+			diffPipelineWebhook,
+			diffClonedAttributes,
+			customdiff.ComputedIf("slug", func(d *schema.ResourceDiff, m interface{}) bool {
+				return d.HasChange("name")
+			}),
+		),
 	}
 }
 
@@ -105,9 +306,10 @@ func pipelineFromSchema(d *schema.ResourceData) *client.Pipeline {
 		boo, _ := strconv.ParseBool(s)
 		return &boo
 	}
-	// Always use GithubSettings as they are a superset of all settings.
-	var provider *buildkiteRest.GitHubSettings
-	if _, ok := d.GetOk("provider_settings"); ok {
+	// Prefer the typed block for the repository's provider. The untyped map always uses
+	// GitHubSettings as they are a superset of all settings.
+	provider := providerSettingsFromSchema(d)
+	if _, ok := d.GetOk("provider_settings"); ok && provider == nil {
 		settings := d.Get("provider_settings").(map[string]interface{})
 		provider = &buildkiteRest.GitHubSettings{
 			TriggerMode:                             strPtr(settings["trigger_mode"]),
@@ -146,18 +348,133 @@ func pipelineFromSchema(d *schema.ResourceData) *client.Pipeline {
 		},
 	}
 }
+
+// validateBranchFilter checks that a branch filter pattern is well formed, as a malformed pattern
+// silently stops builds from being triggered.
+func validateBranchFilter(v interface{}, k string) ([]string, []error) {
+	if _, err := branchfilter.Parse(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// validateConditional checks the syntax of a conditional expression, e.g. a step's `if`.
+func validateConditional(v interface{}, k string) ([]string, []error) {
+	if _, err := conditional.Parse(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// validateProviderSettings checks the branch filter and filter condition in the untyped provider
+// settings.
+func validateProviderSettings(v interface{}, k string) ([]string, []error) {
+	settings := v.(map[string]interface{})
+	var errs []error
+	if filter, ok := settings["pull_request_branch_filter_configuration"].(string); ok {
+		_, filterErrs := validateBranchFilter(filter, k+".pull_request_branch_filter_configuration")
+		errs = append(errs, filterErrs...)
+	}
+	if condition, ok := settings["filter_condition"].(string); ok && condition != "" {
+		_, conditionErrs := validateConditional(condition, k+".filter_condition")
+		errs = append(errs, conditionErrs...)
+	}
+	return nil, errs
+}
+
+// validateTimeouts checks that the default timeout for command steps is within the maximum.
+func validateTimeouts(d *schema.ResourceDiff, m interface{}) error {
+	defaultTimeout := d.Get("default_timeout_in_minutes").(int)
+	maximumTimeout := d.Get("maximum_timeout_in_minutes").(int)
+	if defaultTimeout != 0 && maximumTimeout != 0 && defaultTimeout > maximumTimeout {
+		return fmt.Errorf("default_timeout_in_minutes (%d) must not exceed maximum_timeout_in_minutes (%d)", defaultTimeout, maximumTimeout)
+	}
+	return nil
+}
+
+// pipelineDetailsFromSchema returns the attributes of the pipeline that are managed through the
+// GraphQL API.
+func pipelineDetailsFromSchema(d *schema.ResourceData) *client.PipelineDetails {
+	details := &client.PipelineDetails{
+		ID:         graphql.String(d.Id()),
+		Visibility: graphql.String(strings.ToUpper(d.Get("visibility").(string))),
+		Emoji:      graphql.String(d.Get("emoji").(string)),
+		Color:      graphql.String(d.Get("color").(string)),
+	}
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		details.Tags = append(details.Tags, client.PipelineTag{Label: graphql.String(tag.(string))})
+	}
+	details.Cluster.ID = graphql.String(d.Get("cluster_id").(string))
+	details.PipelineTemplate.ID = graphql.String(d.Get("pipeline_template_id").(string))
+	details.DefaultTimeoutInMinutes = graphql.Int(d.Get("default_timeout_in_minutes").(int))
+	details.MaximumTimeoutInMinutes = graphql.Int(d.Get("maximum_timeout_in_minutes").(int))
+	return details
+}
+
+// pipelineDetailsAttributes are the attributes that are only sent to Buildkite with
+// UpdatePipelineDetails, as the REST API doesn't support them.
+var pipelineDetailsAttributes = []string{
+	"visibility",
+	"emoji",
+	"color",
+	"tags",
+	"cluster_id",
+	"pipeline_template_id",
+	"default_timeout_in_minutes",
+	"maximum_timeout_in_minutes",
+}
+
 func createPipeline(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
+	if err := clonePipelineSource(d, bk); err != nil {
+		return err
+	}
+	if err := setRenderedSteps(d); err != nil {
+		return err
+	}
+	teamUUIDs, err := teamUUIDsFromSchema(d, bk)
+	if err != nil {
+		return err
+	}
 	p := pipelineFromSchema(d)
-	if err := bk.CreatePipeline(p); err != nil {
+	if p.Configuration, err = pipelineConfiguration(d); err != nil {
+		return err
+	}
+	if err := bk.CreatePipeline(p, teamUUIDs); err != nil {
 		return err
 	}
 	d.Set("slug", p.Slug)
-	return readPipeline(d, m)
+	id, err := bk.GetPipelineID(*p.Slug)
+	if err != nil {
+		return err
+	}
+	d.SetId(id)
+	// Teams are given a default access level on create.
+	if err := syncPipelineTeams(d, bk); err != nil {
+		return err
+	}
+	// The REST API can't create pipelines with these, so they are set right after creating it.
+	for _, attribute := range pipelineDetailsAttributes {
+		if _, ok := d.GetOk(attribute); ok {
+			if err := bk.UpdatePipelineDetails(pipelineDetailsFromSchema(d)); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if d.Get("archived").(bool) {
+		if err := bk.ArchivePipeline(id); err != nil {
+			return err
+		}
+	}
+	if err := readPipeline(d, m); err != nil {
+		return err
+	}
+	return createPipelineWebhook(d, bk)
 }
 
 func readPipeline(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	slug := d.Get("slug").(string)
 	p, err := bk.ReadPipeline(slug)
 	if err != nil {
@@ -165,15 +482,37 @@ func readPipeline(d *schema.ResourceData, m interface{}) error {
 	}
 
 	// Set the ID to the gql ID so it can be used by other resources.
-	id, err := bk.GetPipelineID(slug)
+	details, err := bk.ReadPipelineDetails(slug)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(id)
+	d.SetId(string(details.ID))
+	d.Set("archived", bool(details.Archived))
+	d.Set("visibility", strings.ToLower(string(details.Visibility)))
+	d.Set("emoji", string(details.Emoji))
+	d.Set("color", string(details.Color))
+	tags := make([]interface{}, 0, len(details.Tags))
+	for _, tag := range details.Tags {
+		tags = append(tags, string(tag.Label))
+	}
+	d.Set("tags", tags)
+	d.Set("cluster_id", string(details.Cluster.ID))
+	d.Set("pipeline_template_id", string(details.PipelineTemplate.ID))
+	d.Set("default_timeout_in_minutes", int(details.DefaultTimeoutInMinutes))
+	d.Set("maximum_timeout_in_minutes", int(details.MaximumTimeoutInMinutes))
 	// Terraform handles pointers gracefully.
+	d.Set("uuid", p.ID)
+	d.Set("name", p.Name)
+	d.Set("web_url", p.WebURL)
+	d.Set("badge_url", p.BadgeURL)
 	d.Set("repository", p.Repository)
-	d.Set("steps", p.Configuration)
+	// Pipelines using a template run the template's configuration rather than their own.
+	if details.PipelineTemplate.ID == "" {
+		if err := readPipelineConfiguration(d, p.Configuration); err != nil {
+			return err
+		}
+	}
 	d.Set("branch_configuration", p.BranchConfiguration)
 	d.Set("cancel_running_branch_builds", p.CancelRunningBranchBuilds)
 	d.Set("cancel_running_branch_builds_filter", p.CancelRunningBranchBuildsFilter)
@@ -182,65 +521,191 @@ func readPipeline(d *schema.ResourceData, m interface{}) error {
 	d.Set("skip_queued_branch_builds", p.SkipQueuedBranchBuilds)
 	d.Set("skip_queued_branch_builds_filter", p.SkipQueuedBranchBuildsFilter)
 
-	boolPtrToStr := func(b *bool) string {
-		if b == nil {
-			return ""
-		}
-		return strconv.FormatBool(*b)
+	// The settings type depends on the repository's provider, e.g. GitHub or Bitbucket.
+	var settings buildkiteRest.ProviderSettings
+	if p.Provider != nil {
+		settings = p.Provider.Settings
+		d.Set("webhook_url", p.Provider.WebhookURL)
+		d.Set("repository_provider", p.Provider.ID)
+	}
+	if err := setProviderSettings(d, settings); err != nil {
+		return err
+	}
+	return readPipelineTeams(d, bk)
+}
+
+// readPipelineConfiguration sets steps, and the attributes that are merged into them, from the
+// configuration read from Buildkite.
+func readPipelineConfiguration(d *schema.ResourceData, configuration string) error {
+	configuration, err := readPipelineDefaults(d, configuration)
+	if err != nil {
+		return err
 	}
-	safeString := func(s *string) string {
-		if s == nil {
-			return ""
+	configuration = configuredSteps(d, configuration)
+	// Keep the configured steps unless they no longer match what Buildkite has, so that
+	// formatting and comments are preserved.
+	if !stepsEqual(d.Get("steps").(string), configuration) {
+		d.Set("steps", configuration)
+	}
+	// Only read structured steps back if they are in use, otherwise they would conflict with steps.
+	if l, ok := d.Get("step").([]interface{}); ok && len(l) > 0 {
+		steps, err := flattenStepBlocks(configuration)
+		if err != nil {
+			return err
 		}
-		return *s
-	}
-	settings := p.Provider.Settings.(*buildkiteRest.GitHubSettings)
-	provider := map[string]interface{}{
-		"trigger_mode":                                  safeString(settings.TriggerMode),
-		"build_pull_requests":                           boolPtrToStr(settings.BuildPullRequests),
-		"pull_request_branch_filter_enabled":            boolPtrToStr(settings.PullRequestBranchFilterEnabled),
-		"pull_request_branch_filter_configuration":      safeString(settings.PullRequestBranchFilterConfiguration),
-		"skip_pull_request_builds_for_existing_commits": boolPtrToStr(settings.SkipPullRequestBuildsForExistingCommits),
-		"build_pull_request_forks":                      boolPtrToStr(settings.BuildPullRequestForks),
-		"prefix_pull_request_fork_branch_names":         boolPtrToStr(settings.PrefixPullRequestForkBranchNames),
-		"build_tags":                                    boolPtrToStr(settings.BuildTags),
-		"publish_commit_status":                         boolPtrToStr(settings.PublishCommitStatus),
-		"publish_commit_status_per_step":                boolPtrToStr(settings.PublishCommitStatusPerStep),
-		"filter_enabled":                                boolPtrToStr(settings.FilterEnabled),
-		"filter_condition":                              safeString(settings.FilterCondition),
-		"separate_pull_request_statuses":                boolPtrToStr(settings.SeparatePullRequestStatuses),
-		"publish_blocked_as_pending":                    boolPtrToStr(settings.PublishBlockedAsPending),
-	}
-
-	// Delete nil values.
-	for k, v := range provider {
-		val := v.(string)
-		if val == "" {
-			delete(provider, k)
+		if err := d.Set("step", steps); err != nil {
+			return err
 		}
 	}
-	d.Set("provider_settings", provider)
 	return nil
 }
 
+// pipelineBuildkiteAttributes are the attributes that are sent to Buildkite when they change. The
+// others, e.g. deletion_protection, only change how Terraform manages the pipeline.
+var pipelineBuildkiteAttributes = []string{
+	"name",
+	"repository",
+	"steps",
+	"step",
+	"steps_interpolation",
+	"interpolated_variables",
+	"env",
+	"default_agents",
+	"notify",
+	"team",
+	"visibility",
+	"emoji",
+	"color",
+	"tags",
+	"cluster_id",
+	"pipeline_template_id",
+	"default_timeout_in_minutes",
+	"maximum_timeout_in_minutes",
+	"branch_configuration",
+	"cancel_running_branch_builds",
+	"cancel_running_branch_builds_filter",
+	"default_branch",
+	"description",
+	"skip_queued_branch_builds",
+	"skip_queued_branch_builds_filter",
+	"provider_settings",
+	"github_settings",
+	"github_enterprise_settings",
+	"bitbucket_settings",
+}
+
 func updatePipeline(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
-	if err := bk.UpdatePipeline(pipelineFromSchema(d)); err != nil {
+	bk := m.(*providerConfig).client
+	if err := setRenderedSteps(d); err != nil {
 		return err
 	}
-	return readPipeline(d, m)
+	wasArchived, archived := d.GetChange("archived")
+	if d.HasChanges(pipelineBuildkiteAttributes...) {
+		// Archived pipelines can't be updated, so they are unarchived while updating. If the update
+		// fails they are archived again rather than being left unarchived.
+		if wasArchived.(bool) {
+			if err := bk.UnarchivePipeline(d.Id()); err != nil {
+				return err
+			}
+		}
+		if err := updatePipelineSettings(d, bk); err != nil {
+			if wasArchived.(bool) {
+				if archiveErr := bk.ArchivePipeline(d.Id()); archiveErr != nil {
+					return fmt.Errorf("%w, and archiving the pipeline again failed: %v", err, archiveErr)
+				}
+			}
+			return err
+		}
+		wasArchived = false
+	}
+	if archived.(bool) != wasArchived.(bool) {
+		if archived.(bool) {
+			if err := bk.ArchivePipeline(d.Id()); err != nil {
+				return err
+			}
+		} else if err := bk.UnarchivePipeline(d.Id()); err != nil {
+			return err
+		}
+	}
+	if err := readPipeline(d, m); err != nil {
+		return err
+	}
+	return createPipelineWebhook(d, bk)
+}
+
+// updatePipelineSettings sends the pipelineBuildkiteAttributes to Buildkite.
+func updatePipelineSettings(d *schema.ResourceData, bk *client.Client) error {
+	p := pipelineFromSchema(d)
+	configuration, err := pipelineConfiguration(d)
+	if err != nil {
+		return err
+	}
+	p.Configuration = configuration
+	if err := bk.UpdatePipeline(p); err != nil {
+		return err
+	}
+	// Buildkite derives the slug from the name, so renaming a pipeline can change it. The gql ID
+	// stays the same so resources referencing the pipeline are unaffected.
+	d.Set("slug", p.Slug)
+	if d.HasChanges(pipelineDetailsAttributes...) {
+		if err := bk.UpdatePipelineDetails(pipelineDetailsFromSchema(d)); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("team") {
+		return syncPipelineTeams(d, bk)
+	}
+	return nil
 }
 
 func deletePipeline(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("pipeline %s can't be destroyed while deletion_protection is set", d.Get("slug").(string))
+	}
+	if d.Get("archive_on_destroy").(bool) {
+		if d.Get("archived").(bool) {
+			return nil
+		}
+		return bk.ArchivePipeline(d.Id())
+	}
+	if err := stopPipelineBuilds(d, bk); err != nil {
+		return err
+	}
 	if err := bk.DeletePipeline(pipelineFromSchema(d)); err != nil {
 		return err
 	}
 	return nil
 }
 
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isPipelineGraphQLID reports whether id is a gql ID for a pipeline. These are base64 encoded and
+// decode to Pipeline---<uuid>.
+func isPipelineGraphQLID(id string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(id)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(string(decoded), "Pipeline---")
+}
+
+// importPipeline accepts a pipeline's slug, UUID or gql ID.
 func importPipeline(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	d.Set("name", d.Id())
+	bk := m.(*providerConfig).client
+	id := d.Id()
+	slug := id
+	var err error
+	switch {
+	case uuidPattern.MatchString(id):
+		slug, err = bk.GetPipelineSlugByUUID(id)
+	case isPipelineGraphQLID(id):
+		slug, err = bk.GetPipelineSlugByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.Set("slug", slug)
 	if err := readPipeline(d, m); err != nil {
 		return nil, err
 	}
@@ -54,6 +54,7 @@ func resourcePipelineSchedule() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: scanPipelineScheduleSecrets,
 	}
 }
 
@@ -83,7 +84,7 @@ func expandSlice(s []string) (map[string]interface{}, error) {
 }
 
 func createPipelineSchedule(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 
 	var env map[string]interface{}
 	if v, ok := d.GetOk("env"); ok {
@@ -113,7 +114,7 @@ func createPipelineSchedule(d *schema.ResourceData, m interface{}) error {
 }
 
 func readPipelineSchedule(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	ps, err := bk.ReadPipelineSchedule(d.Id())
 	if err != nil {
 		return err
@@ -140,7 +141,7 @@ func readPipelineSchedule(d *schema.ResourceData, m interface{}) error {
 }
 
 func updatePipelineSchedule(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 
 	var env map[string]interface{}
 	if v, ok := d.GetOk("env"); ok {
@@ -164,7 +165,7 @@ func updatePipelineSchedule(d *schema.ResourceData, m interface{}) error {
 }
 
 func deletePipelineSchedule(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	id := d.Id()
 	if err := bk.DeletePipelineSchedule(&client.PipelineSchedule{ID: graphql.String(id)}); err != nil {
 		return err
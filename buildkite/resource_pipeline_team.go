@@ -36,7 +36,7 @@ func resourceTeamPipeline() *schema.Resource {
 }
 
 func createPipelineTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 
 	tp := &client.TeamPipeline{
 		AccessLevel: graphql.String(d.Get("access_level").(string)),
@@ -56,7 +56,7 @@ func createPipelineTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func readPipelineTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	tp, err := bk.ReadTeamPipeline(d.Id())
 	if err != nil {
 		return err
@@ -69,7 +69,7 @@ func readPipelineTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func updatePipelineTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 
 	tp := &client.TeamPipeline{
 		ID:          graphql.String(d.Id()),
@@ -88,7 +88,7 @@ func updatePipelineTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func deletePipelineTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	id := d.Id()
 	if err := bk.DeleteTeamPipeline(&client.TeamPipeline{ID: graphql.String(id)}); err != nil {
 		return err
@@ -0,0 +1,97 @@
+package buildkite
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+	"github.com/shurcooL/graphql"
+)
+
+func resourcePipelineTemplate() *schema.Resource {
+	return &schema.Resource{
+		Description: "A resource representing a pipeline template in Buildkite. Pipelines using a template run its step configuration instead of their own.",
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"configuration": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The step configuration as YAML or JSON. Changes to formatting, key order or comments are not considered a diff.",
+				DiffSuppressFunc: suppressEquivalentSteps,
+			},
+			"available": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether anyone creating a pipeline can choose the template, rather than only administrators.",
+			},
+		},
+		Create: createPipelineTemplate,
+		Read:   readPipelineTemplate,
+		Update: updatePipelineTemplate,
+		Delete: deletePipelineTemplate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+func pipelineTemplateFromSchema(d *schema.ResourceData) *client.PipelineTemplate {
+	return &client.PipelineTemplate{
+		ID:            graphql.String(d.Id()),
+		Name:          graphql.String(d.Get("name").(string)),
+		Description:   graphql.String(d.Get("description").(string)),
+		Configuration: graphql.String(d.Get("configuration").(string)),
+		Available:     graphql.Boolean(d.Get("available").(bool)),
+	}
+}
+
+func createPipelineTemplate(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	template := pipelineTemplateFromSchema(d)
+	if err := bk.CreatePipelineTemplate(template); err != nil {
+		return err
+	}
+	d.SetId(string(template.ID))
+	return readPipelineTemplate(d, m)
+}
+
+func readPipelineTemplate(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	template, err := bk.ReadPipelineTemplate(d.Id())
+	if err != nil {
+		return err
+	}
+	if template.ID == "" {
+		// The template was deleted outside of Terraform.
+		d.SetId("")
+		return nil
+	}
+	d.Set("name", template.Name)
+	d.Set("description", template.Description)
+	// Keep the configured configuration unless it no longer matches, so that formatting and
+	// comments are preserved.
+	if !stepsEqual(d.Get("configuration").(string), string(template.Configuration)) {
+		d.Set("configuration", template.Configuration)
+	}
+	d.Set("available", template.Available)
+	return nil
+}
+
+func updatePipelineTemplate(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	if err := bk.UpdatePipelineTemplate(pipelineTemplateFromSchema(d)); err != nil {
+		return err
+	}
+	return readPipelineTemplate(d, m)
+}
+
+func deletePipelineTemplate(d *schema.ResourceData, m interface{}) error {
+	bk := m.(*providerConfig).client
+	return bk.DeletePipelineTemplate(&client.PipelineTemplate{ID: graphql.String(d.Id())})
+}
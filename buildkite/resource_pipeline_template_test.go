@@ -0,0 +1,109 @@
+package buildkite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/client"
+	"github.com/shurcooL/graphql"
+)
+
+func testAccPipelineTemplateConfig(name string, available bool) string {
+	return fmt.Sprintf(`
+resource "buildkite_pipeline_template" "test" {
+	name = "%s"
+	description = "Uploads the pipeline from the repository"
+	configuration = <<EOF
+steps:
+  - label: ":pipeline:"
+    command: "buildkite-agent pipeline upload"
+EOF
+	available = %t
+}
+
+resource "buildkite_pipeline" "test" {
+	name = "%s"
+	repository = "%s"
+	steps = <<EOF
+steps:
+  - label: "test things"
+    command: "make test"
+EOF
+
+	pipeline_template_id = buildkite_pipeline_template.test.id
+}
+`, name, available, name, repoName)
+}
+
+func TestAccPipelineTemplate(t *testing.T) {
+	rName := acctest.RandString(5)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactory,
+		CheckDestroy:      testAccPipelineTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPipelineTemplateConfig(rName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccPipelineTemplateExists("buildkite_pipeline_template.test"),
+					resource.TestCheckResourceAttr("buildkite_pipeline_template.test", "name", rName),
+					resource.TestCheckResourceAttr("buildkite_pipeline_template.test", "available", "false"),
+					resource.TestCheckResourceAttrPair("buildkite_pipeline.test", "pipeline_template_id", "buildkite_pipeline_template.test", "id"),
+				),
+			},
+			{
+				Config: testAccPipelineTemplateConfig(rName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("buildkite_pipeline_template.test", "available", "true"),
+				),
+			},
+			{
+				ResourceName:      "buildkite_pipeline_template.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccPipelineTemplateExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Resource %s not found", name)
+		}
+		template, err := cli.ReadPipelineTemplate(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if template.ID == "" {
+			return fmt.Errorf("Pipeline template %s not found", rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccPipelineTemplateDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "buildkite_pipeline_template" {
+			continue
+		}
+		template, err := cli.ReadPipelineTemplate(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if template.ID != "" {
+			toDelete := &client.PipelineTemplate{ID: graphql.String(rs.Primary.ID)}
+			if err := cli.DeletePipelineTemplate(toDelete); err != nil {
+				return err
+			}
+			return fmt.Errorf("Pipeline template %s still exists", rs.Primary.ID)
+		}
+	}
+	return testAccPipelineDestroy(s)
+}
@@ -41,7 +41,7 @@ func resourceTeam() *schema.Resource {
 }
 
 func createTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	name := d.Get("name").(string)
 	privacy := d.Get("privacy").(string)
 	isDefaultTeam := d.Get("is_default_team").(bool)
@@ -60,7 +60,7 @@ func createTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func readTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	team, err := bk.ReadTeam(d.Id())
 	if err != nil {
 		return err
@@ -73,7 +73,7 @@ func readTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func updateTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	id := d.Id()
 	name := d.Get("name").(string)
 	privacy := d.Get("privacy").(string)
@@ -93,7 +93,7 @@ func updateTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteTeam(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	id := d.Id()
 	if err := bk.DeleteTeam(&client.Team{ID: graphql.String(id)}); err != nil {
 		return err
@@ -102,7 +102,7 @@ func deleteTeam(d *schema.ResourceData, m interface{}) error {
 }
 
 func importTeam(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	team, err := bk.ReadTeamByName(d.Id())
 	if err != nil {
 		return nil, err
@@ -28,7 +28,7 @@ func resourceTeamMember() *schema.Resource {
 }
 
 func createTeamMember(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	member := &client.TeamMember{
 		UserID: graphql.String(d.Get("user_id").(string)),
 		TeamID: graphql.String(d.Get("team_id").(string)),
@@ -42,7 +42,7 @@ func createTeamMember(d *schema.ResourceData, m interface{}) error {
 }
 
 func readTeamMember(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	member, err := bk.ReadTeamMember(d.Id())
 	if err != nil {
 		return err
@@ -53,7 +53,7 @@ func readTeamMember(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteTeamMember(d *schema.ResourceData, m interface{}) error {
-	bk := m.(*client.Client)
+	bk := m.(*providerConfig).client
 	member := &client.TeamMember{
 		ID: graphql.String(d.Id()),
 	}
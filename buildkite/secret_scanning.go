@@ -0,0 +1,106 @@
+package buildkite
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/samsara-dev/terraform-provider-buildkite/buildkite/secrets"
+)
+
+// Values of the provider's secret_scanning setting.
+const (
+	secretScanningOff   = "off"
+	secretScanningWarn  = "warn"
+	secretScanningError = "error"
+)
+
+// secretScanning decides what happens to values that look like secrets. The SDK can't show warnings
+// in the plan, so they are logged.
+type secretScanning struct {
+	mode      string
+	allowlist []*regexp.Regexp
+}
+
+func secretScanningFromSchema(d *schema.ResourceData) (*secretScanning, error) {
+	s := &secretScanning{mode: d.Get("secret_scanning").(string)}
+	for _, pattern := range d.Get("secret_scanning_allowlist").([]interface{}) {
+		re, err := regexp.Compile(stringValue(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("secret_scanning_allowlist: %w", err)
+		}
+		s.allowlist = append(s.allowlist, re)
+	}
+	return s, nil
+}
+
+// check reports the findings for an attribute that aren't allowlisted, failing the plan if
+// configured to.
+func (s *secretScanning) check(attribute string, findings []*secrets.Finding) error {
+	if s.mode == secretScanningOff {
+		return nil
+	}
+	var msgs []string
+	for _, f := range findings {
+		if !s.allowed(f.Value) {
+			msgs = append(msgs, f.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%s may contain secrets, which would be stored in the Terraform state and the Buildkite pipeline. "+
+		"Read them from the agent environment or a secrets manager instead, or mark false positives with a %q comment or secret_scanning_allowlist:\n%s",
+		attribute, secrets.AllowAnnotation, strings.Join(msgs, "\n"))
+	if s.mode == secretScanningWarn {
+		log.Printf("[WARN] %s", msg)
+		return nil
+	}
+	return errors.New(msg)
+}
+
+func (s *secretScanning) allowed(value string) bool {
+	for _, re := range s.allowlist {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanPipelineSecrets looks for secrets in the pipeline's configuration and environment.
+func scanPipelineSecrets(d *schema.ResourceDiff, m interface{}) error {
+	config, ok := m.(*providerConfig)
+	if !ok {
+		return nil
+	}
+	if d.NewValueKnown("steps") {
+		if err := config.secretScanning.check("steps", secrets.Scan(d.Get("steps").(string))); err != nil {
+			return err
+		}
+	}
+	return scanEnvSecrets(d, config)
+}
+
+// scanPipelineScheduleSecrets looks for secrets in the environment of scheduled builds.
+func scanPipelineScheduleSecrets(d *schema.ResourceDiff, m interface{}) error {
+	config, ok := m.(*providerConfig)
+	if !ok {
+		return nil
+	}
+	return scanEnvSecrets(d, config)
+}
+
+func scanEnvSecrets(d *schema.ResourceDiff, config *providerConfig) error {
+	if !d.NewValueKnown("env") {
+		return nil
+	}
+	env := map[string]string{}
+	for k, v := range d.Get("env").(map[string]interface{}) {
+		env[k] = stringValue(v)
+	}
+	return config.secretScanning.check("env", secrets.ScanEnv(env))
+}
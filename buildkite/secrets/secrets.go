@@ -0,0 +1,235 @@
+// Package secrets looks for credentials written into pipeline configurations and environment
+// variables, so that they don't end up in Terraform state and Buildkite's pipeline settings.
+package secrets
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AllowAnnotation marks a line of a configuration as free of secrets when it appears in a comment on
+// that line or on a line of its own right above it.
+const AllowAnnotation = "secret-scan:allow"
+
+// Finding is a value that looks like a secret.
+type Finding struct {
+	// Line is the line of the configuration the value is on, starting from 1. It is 0 for
+	// environment variables that were not scanned as part of a configuration.
+	Line int
+	// Key is the name of the environment variable holding the value, if any.
+	Key string
+	// Rule describes what the value looks like, e.g. "AWS access key ID".
+	Rule string
+	// Value is the value that looks like a secret.
+	Value string
+}
+
+// String describes the finding without revealing the value.
+func (f *Finding) String() string {
+	var location string
+	switch {
+	case f.Line > 0 && f.Key != "":
+		location = fmt.Sprintf("line %d: %s: ", f.Line, f.Key)
+	case f.Line > 0:
+		location = fmt.Sprintf("line %d: ", f.Line)
+	case f.Key != "":
+		location = f.Key + ": "
+	}
+	return fmt.Sprintf("%s%s %q", location, f.Rule, redact(f.Value))
+}
+
+// redact keeps enough of a value to find it again.
+func redact(value string) string {
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", len(value)-4)
+}
+
+type tokenFormat struct {
+	rule    string
+	pattern *regexp.Regexp
+}
+
+// tokenFormats are credentials recognizable by their format alone.
+var tokenFormats = []tokenFormat{
+	{rule: "AWS access key ID", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`)},
+	{rule: "GitHub token", pattern: regexp.MustCompile(`\bgh[opsur]_[A-Za-z0-9]{36}\b`)},
+	{rule: "GitHub token", pattern: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{82}\b`)},
+	{rule: "Buildkite API access token", pattern: regexp.MustCompile(`\bbkua_[a-f0-9]{40}\b`)},
+}
+
+// agentTokenKey matches the names of variables holding Buildkite agent tokens, which have no
+// recognizable format.
+var agentTokenKey = regexp.MustCompile(`(?i)agent_token$`)
+
+// candidate matches the substrings of a value considered for the entropy check.
+var candidate = regexp.MustCompile(`[A-Za-z0-9+/=_\-]{20,}`)
+
+var interpolation = regexp.MustCompile(`^\$(?:\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Za-z_][A-Za-z0-9_]*)$`)
+
+var hexString = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// Entropy thresholds in bits per character. Random base64 reaches 4 bits within 20 characters,
+// while words and identifiers of that length rarely exceed 3.5. Hex strings must be longer than a
+// commit SHA.
+const (
+	base64Entropy = 4.0
+	hexEntropy    = 3.0
+	hexMinLength  = 41
+)
+
+// Scan looks for secrets in a pipeline configuration. Known token formats are found anywhere,
+// including comments and commands, while the values of `env` mappings are also checked for
+// agent tokens and high entropy strings. Lines carrying AllowAnnotation are skipped.
+func Scan(config string) []*Finding {
+	lines := strings.Split(config, "\n")
+	allowed := func(line int) bool {
+		if line < 1 || line > len(lines) {
+			return false
+		}
+		if strings.Contains(lines[line-1], AllowAnnotation) {
+			return true
+		}
+		if line > 1 {
+			previous := strings.TrimSpace(lines[line-2])
+			return strings.HasPrefix(previous, "#") && strings.Contains(previous, AllowAnnotation)
+		}
+		return false
+	}
+
+	var findings []*Finding
+	seen := map[string]bool{}
+	add := func(f *Finding) {
+		id := fmt.Sprintf("%d\x00%s", f.Line, f.Value)
+		if seen[id] || allowed(f.Line) {
+			return
+		}
+		seen[id] = true
+		findings = append(findings, f)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(config), &root); err == nil && len(root.Content) > 0 {
+		walkEnv(root.Content[0], func(key, value *yaml.Node) {
+			for _, f := range scanValue(key.Value, value.Value) {
+				f.Line = value.Line
+				add(f)
+			}
+		})
+	}
+	for i, line := range lines {
+		for _, f := range scanFormats(line) {
+			f.Line = i + 1
+			add(f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings
+}
+
+// ScanEnv looks for secrets in the values of environment variables.
+func ScanEnv(env map[string]string) []*Finding {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var findings []*Finding
+	for _, key := range keys {
+		findings = append(findings, scanValue(key, env[key])...)
+	}
+	return findings
+}
+
+// walkEnv calls fn for each variable of every `env` mapping in the document.
+func walkEnv(node *yaml.Node, fn func(key, value *yaml.Node)) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			walkEnv(n, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "env" && value.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					if value.Content[j+1].Kind == yaml.ScalarNode {
+						fn(value.Content[j], value.Content[j+1])
+					}
+				}
+				continue
+			}
+			walkEnv(value, fn)
+		}
+	}
+}
+
+// scanValue checks the value of an environment variable.
+func scanValue(key, value string) []*Finding {
+	if isInterpolation(value) {
+		return nil
+	}
+	findings := scanFormats(value)
+	for _, f := range findings {
+		f.Key = key
+	}
+	if len(findings) > 0 {
+		return findings
+	}
+	if agentTokenKey.MatchString(key) && value != "" {
+		return []*Finding{{Key: key, Rule: "Buildkite agent token", Value: value}}
+	}
+	for _, s := range candidate.FindAllString(value, -1) {
+		if isHighEntropy(s) {
+			findings = append(findings, &Finding{Key: key, Rule: "high entropy string", Value: s})
+		}
+	}
+	return findings
+}
+
+func scanFormats(s string) []*Finding {
+	var findings []*Finding
+	for _, format := range tokenFormats {
+		for _, match := range format.pattern.FindAllString(s, -1) {
+			findings = append(findings, &Finding{Rule: format.rule, Value: match})
+		}
+	}
+	return findings
+}
+
+// isInterpolation reports whether a value only refers to another variable, e.g. $API_TOKEN.
+func isInterpolation(value string) bool {
+	return interpolation.MatchString(value)
+}
+
+func isHighEntropy(s string) bool {
+	if !strings.ContainsAny(s, "0123456789") || !strings.ContainsAny(strings.ToLower(s), "abcdefghijklmnopqrstuvwxyz") {
+		return false
+	}
+	if hexString.MatchString(s) {
+		return len(s) >= hexMinLength && entropy(s) > hexEntropy
+	}
+	return entropy(s) > base64Entropy
+}
+
+// entropy is the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var e float64
+	for _, count := range counts {
+		p := float64(count) / float64(len(s))
+		e -= p * math.Log2(p)
+	}
+	return e
+}